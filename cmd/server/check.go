@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/config"
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/health"
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/models"
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/redact"
+)
+
+// checkResult is one row of `aurora check -json`.
+type checkResult struct {
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	Target    string    `json:"target,omitempty"`
+	Status    string    `json:"status"`
+	LatencyMS int64     `json:"latency_ms"`
+	Reason    string    `json:"reason,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	Warning   string    `json:"warning,omitempty"`
+	Attempts  int       `json:"attempts,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// runCheck implements `aurora check [flags] [name...]`: one pass over
+// all (or the named) services. It exits 0 if every checked service is
+// UP or DEGRADED, 1 if any is not, and 2 on usage or config errors.
+func runCheck(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("check", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: aurora check [flags] [service name ...]")
+		flags.PrintDefaults()
+	}
+
+	var cf checkFlags
+	if err := cf.register(flags); err != nil {
+		fmt.Fprintf(stderr, "aurora check: %v\n", err)
+		return 2
+	}
+	asJSON := flags.Bool("json", false, "print results as JSON")
+	if err := flags.Parse(args); err != nil {
+		return parseExit(err)
+	}
+	// Secrets from the config are masked in everything logged.
+	redactor := redact.New()
+	if err := setupLogging(redactor.Writer(stderr), cf.logLevel); err != nil {
+		fmt.Fprintf(stderr, "aurora check: %v\n", err)
+		return 2
+	}
+
+	cfg, err := config.Load(cf.configPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "aurora check: %v\n", err)
+		return 2
+	}
+
+	redactor.Set(cfg.Secrets())
+
+	services, err := selectServices(cfg.Services, flags.Args())
+	if err != nil {
+		fmt.Fprintf(stderr, "aurora check: %v\n", err)
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	checker := health.NewChecker(services, cf.intervalFor(cfg), cf.httpTimeout, cf.tcpTimeout,
+		health.WithWorkers(cfg.Scheduler.Workers), health.WithRedactor(redactor))
+	checker.CheckAll(ctx)
+
+	snap := checker.Snapshot()
+	rows := make([]checkResult, 0, len(services))
+	code := 0
+	for _, svc := range services {
+		res, ok := snap[svc.Name]
+		if !ok {
+			res.Status = health.StatusUnknown
+			res.Error = "not checked"
+		}
+		if !res.Status.IsUp() {
+			code = 1
+		}
+
+		typ := svc.Type
+		if typ == "" {
+			typ = "http"
+		}
+		rows = append(rows, checkResult{
+			Name:      svc.Name,
+			Type:      typ,
+			Target:    res.URL,
+			Status:    string(res.Status),
+			LatencyMS: res.Latency.Milliseconds(),
+			Reason:    string(health.ClassifyError(res.Error)),
+			Error:     res.Error,
+			Warning:   res.Warning,
+			Attempts:  res.Attempts,
+			CheckedAt: res.CheckedAt,
+		})
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(rows); err != nil {
+			fmt.Fprintf(stderr, "aurora check: %v\n", err)
+			return 2
+		}
+		return code
+	}
+
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tTYPE\tSTATUS\tLATENCY\tDETAIL")
+	for _, r := range rows {
+		detail := r.Error
+		if detail == "" {
+			detail = r.Warning
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%dms\t%s\n", r.Name, r.Type, r.Status, r.LatencyMS, detail)
+	}
+	tw.Flush()
+	return code
+}
+
+// selectServices returns the services with the given names, in config
+// order, or all services when names is empty.
+func selectServices(all []models.Service, names []string) ([]models.Service, error) {
+	if len(names) == 0 {
+		return all, nil
+	}
+
+	want := make(map[string]bool, len(names))
+	for _, n := range names {
+		want[n] = true
+	}
+
+	var out []models.Service
+	for _, svc := range all {
+		if want[svc.Name] {
+			out = append(out, svc)
+			delete(want, svc.Name)
+		}
+	}
+	for n := range want {
+		return nil, fmt.Errorf("no service named %q", n)
+	}
+	return out, nil
+}
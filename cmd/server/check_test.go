@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+)
+
+// tcpConfig writes a config with one TCP service per port.
+func tcpConfig(t *testing.T, ports map[string]int) string {
+	t.Helper()
+	var b strings.Builder
+	b.WriteString("services:\n")
+	for name, port := range ports {
+		fmt.Fprintf(&b, "  - name: %s\n    type: tcp\n    host: 127.0.0.1\n    port: %d\n", name, port)
+	}
+	return writeConfig(t, b.String())
+}
+
+// listen returns the port of a listener that accepts until the test ends.
+func listen(t *testing.T) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			c.Close()
+		}
+	}()
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+// closedPort returns a port nothing listens on.
+func closedPort(t *testing.T) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+	ln.Close()
+	return port
+}
+
+func TestCheckExitCodes(t *testing.T) {
+	path := tcpConfig(t, map[string]int{"Up": listen(t), "Down": closedPort(t)})
+
+	if code, stdout, stderr := runArgs(t, "check", "-config", path, "Up"); code != 0 {
+		t.Fatalf("check Up: exit code = %d, want 0\nstdout: %s\nstderr: %s", code, stdout, stderr)
+	}
+	if code, stdout, _ := runArgs(t, "check", "-config", path); code != 1 || !strings.Contains(stdout, "DOWN") {
+		t.Fatalf("check all: exit code = %d, want 1 with a DOWN row\nstdout: %s", code, stdout)
+	}
+	if code, _, stderr := runArgs(t, "check", "-config", path, "Nope"); code != 2 || !strings.Contains(stderr, `no service named "Nope"`) {
+		t.Fatalf("check Nope: exit code = %d, stderr = %q; want 2", code, stderr)
+	}
+}
+
+func TestCheckJSON(t *testing.T) {
+	path := tcpConfig(t, map[string]int{"Up": listen(t)})
+
+	code, stdout, stderr := runArgs(t, "check", "-config", path, "-json")
+	if code != 0 {
+		t.Fatalf("exit code = %d, want 0\nstderr: %s", code, stderr)
+	}
+	var rows []checkResult
+	if err := json.Unmarshal([]byte(stdout), &rows); err != nil {
+		t.Fatalf("decode %q: %v", stdout, err)
+	}
+	if len(rows) != 1 || rows[0].Name != "Up" || rows[0].Type != "tcp" || rows[0].Status != "UP" {
+		t.Fatalf("rows = %+v, want one UP tcp row", rows)
+	}
+}
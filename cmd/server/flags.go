@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"time"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/config"
+)
+
+// checkFlags are the settings shared by serve and check.
+type checkFlags struct {
+	configPath  string
+	logLevel    string
+	interval    time.Duration
+	httpTimeout time.Duration
+	tcpTimeout  time.Duration
+}
+
+// register adds the shared flags to fs, taking defaults from the
+// environment. It fails if an environment variable is malformed.
+func (f *checkFlags) register(fs *flag.FlagSet) error {
+	interval, err := getEnvDuration("AURORA_INTERVAL", 30*time.Second)
+	if err != nil {
+		return err
+	}
+	httpTimeout, err := getEnvDuration("AURORA_HTTP_TIMEOUT", 3*time.Second)
+	if err != nil {
+		return err
+	}
+	tcpTimeout, err := getEnvDuration("AURORA_TCP_TIMEOUT", 2*time.Second)
+	if err != nil {
+		return err
+	}
+
+	fs.StringVar(&f.configPath, "config", getEnv("AURORA_CONFIG", "config.yaml"), "config file (env AURORA_CONFIG)")
+	fs.StringVar(&f.logLevel, "log-level", getEnv("AURORA_LOG_LEVEL", "info"), "debug, info, warn or error (env AURORA_LOG_LEVEL)")
+	fs.DurationVar(&f.interval, "interval", interval, "check interval when the config sets none (env AURORA_INTERVAL)")
+	fs.DurationVar(&f.httpTimeout, "http-timeout", httpTimeout, "HTTP/DNS/TLS timeout when the config sets none (env AURORA_HTTP_TIMEOUT)")
+	fs.DurationVar(&f.tcpTimeout, "tcp-timeout", tcpTimeout, "TCP/ping timeout when the config sets none (env AURORA_TCP_TIMEOUT)")
+	return nil
+}
+
+// intervalFor returns the default check interval: the config's
+// defaults.interval if set, otherwise the flag.
+func (f *checkFlags) intervalFor(cfg *config.Config) time.Duration {
+	if cfg.Defaults.Interval > 0 {
+		return cfg.Defaults.Interval
+	}
+	return f.interval
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+)
+
+// Log levels, ordered by severity. Aurora logs through the standard log
+// package; a line's level comes from its message prefix ("debug:",
+// "warning:", "error:"), anything else is info.
+const (
+	levelDebug = iota
+	levelInfo
+	levelWarn
+	levelError
+)
+
+func parseLevel(s string) (int, error) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return levelDebug, nil
+	case "", "info":
+		return levelInfo, nil
+	case "warn", "warning":
+		return levelWarn, nil
+	case "error":
+		return levelError, nil
+	}
+	return 0, fmt.Errorf("unknown log level %q (want debug, info, warn or error)", s)
+}
+
+// levelWriter drops log lines below min.
+type levelWriter struct {
+	w   io.Writer
+	min int
+}
+
+func (lw *levelWriter) Write(p []byte) (int, error) {
+	if lineLevel(p) < lw.min {
+		return len(p), nil
+	}
+	return lw.w.Write(p)
+}
+
+// lineLevel classifies a line written by a logger using log.LstdFlags.
+func lineLevel(line []byte) int {
+	// Skip the "2006/01/02 15:04:05 " timestamp.
+	if len(line) > 20 {
+		line = line[20:]
+	}
+	switch {
+	case bytes.HasPrefix(line, []byte("debug:")):
+		return levelDebug
+	case bytes.HasPrefix(line, []byte("warning:")):
+		return levelWarn
+	case bytes.HasPrefix(line, []byte("error:")):
+		return levelError
+	}
+	return levelInfo
+}
+
+// setupLogging points the standard logger at w, filtered by level.
+func setupLogging(w io.Writer, level string) error {
+	min, err := parseLevel(level)
+	if err != nil {
+		return err
+	}
+	log.SetFlags(log.LstdFlags)
+	log.SetOutput(&levelWriter{w: w, min: min})
+	return nil
+}
@@ -1,61 +1,92 @@
+// Command aurora runs the Aurora Homelab dashboard.
+//
+//	aurora [serve] [flags]          run the dashboard and health checker
+//	aurora check [flags] [name...]  check services once and print the results
+//	aurora validate [config.yaml]   validate a config file
+//	aurora version                  print version information
+//
+// Most flags can also be set through AURORA_* environment variables;
+// an explicit flag wins over the environment.
 package main
 
 import (
-	"log"
-	"net/http"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
 	"os"
+	"strings"
 	"time"
-
-	"github.com/cyber-mountain-man/aurora-homelab-go/internal/config"
-	"github.com/cyber-mountain-man/aurora-homelab-go/internal/handlers"
-	"github.com/cyber-mountain-man/aurora-homelab-go/internal/health"
 )
 
-func getEnv(key, fallback string) string {
-	if v := os.Getenv(key); v != "" {
-		return v
-	}
-	return fallback
-}
-
 func main() {
-	addr := getEnv("AURORA_ADDR", ":8080")
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
 
-	// Load configuration (services, etc.).
-	cfg, err := config.Load("config.yaml")
-	if err != nil {
-		log.Printf("warning: could not load config.yaml: %v", err)
-		cfg = &config.Config{}
+// run dispatches to a subcommand and returns the process exit code.
+// Without a subcommand (or with only flags) it serves, as before.
+func run(args []string, stdout, stderr io.Writer) int {
+	cmd := "serve"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		cmd, args = args[0], args[1:]
 	}
 
-	// Health checker: run every 30s, 3s timeout per service.
-	checker := health.NewChecker(
-		cfg.Services,
-		30*time.Second, // interval
-		3*time.Second,  // HTTP timeout
-		2*time.Second,  // TCP timeout
-	)
+	switch cmd {
+	case "serve":
+		return runServe(args, stderr)
+	case "check":
+		return runCheck(args, stdout, stderr)
+	case "validate":
+		return runValidate(args, stdout, stderr)
+	case "version":
+		return runVersion(stdout)
+	case "help":
+		usage(stdout)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "aurora: unknown command %q\n\n", cmd)
+		usage(stderr)
+		return 2
+	}
+}
 
-	checker.Start()
+func usage(w io.Writer) {
+	fmt.Fprint(w, `usage: aurora <command> [flags]
 
-	mux := http.NewServeMux()
+commands:
+  serve      run the dashboard and health checker (default)
+  check      check services once and print the results
+  validate   validate a config file
+  version    print version information
 
-	// Static files (CSS, JS, images)
-	fileServer := http.FileServer(http.Dir("./web/static"))
-	mux.Handle("/static/", http.StripPrefix("/static/", fileServer))
+Run "aurora <command> -h" for the flags of a command.
+`)
+}
 
-	// Dashboard handler with services + health checker.
-	dh, err := handlers.NewDashboardHandler("./web/templates", cfg.Services, checker)
-	if err != nil {
-		log.Fatalf("failed to initialize dashboard handler: %v", err)
+// parseExit is the exit code for a flag parse error: 0 for -h, else 2.
+func parseExit(err error) int {
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
 	}
+	return 2
+}
 
-	mux.HandleFunc("/", dh.Dashboard)
-	mux.HandleFunc("/dashboard/partial", dh.DashboardPartial)
-	mux.HandleFunc("/services/recheck", dh.RecheckService)
+func getEnv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
 
-	log.Printf("Aurora Homelab listening on %s", addr)
-	if err := http.ListenAndServe(addr, mux); err != nil {
-		log.Fatalf("server stopped with error: %v", err)
+// getEnvDuration is getEnv for durations such as "30s".
+func getEnvDuration(key string, fallback time.Duration) (time.Duration, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", key, err)
 	}
+	return d, nil
 }
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// runArgs runs the command line args and returns the exit code and
+// what was written to stdout and stderr.
+func runArgs(t *testing.T, args ...string) (int, string, string) {
+	t.Helper()
+	// check and serve point the standard logger at stderr.
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	var stdout, stderr bytes.Buffer
+	code := run(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func writeConfig(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	return path
+}
+
+func TestRunExitCodes(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stdout string
+		stderr string
+	}{
+		{name: "help", args: []string{"help"}, code: 0, stdout: "usage: aurora"},
+		{name: "version", args: []string{"version"}, code: 0, stdout: "aurora dev"},
+		{name: "unknown command", args: []string{"frobnicate"}, code: 2, stderr: `unknown command "frobnicate"`},
+		{name: "serve help", args: []string{"-h"}, code: 0, stderr: "-addr"},
+		{name: "serve unknown flag", args: []string{"-nope"}, code: 2, stderr: "flag provided but not defined: -nope"},
+		{name: "serve bad log level", args: []string{"serve", "-log-level", "loud"}, code: 2, stderr: `unknown log level "loud"`},
+		{name: "check help", args: []string{"check", "-h"}, code: 0, stderr: "usage: aurora check"},
+		{name: "check bad log level", args: []string{"check", "-log-level", "loud"}, code: 2, stderr: `unknown log level "loud"`},
+		{name: "check missing config", args: []string{"check", "-config", "does-not-exist.yaml"}, code: 2, stderr: "does-not-exist.yaml"},
+		{name: "validate help", args: []string{"validate", "-h"}, code: 0, stderr: "usage: aurora validate"},
+		{name: "validate unknown flag", args: []string{"validate", "-nope"}, code: 2, stderr: "flag provided but not defined: -nope"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runArgs(t, tt.args...)
+			if code != tt.code {
+				t.Errorf("exit code = %d, want %d\nstdout: %s\nstderr: %s", code, tt.code, stdout, stderr)
+			}
+			if !strings.Contains(stdout, tt.stdout) {
+				t.Errorf("stdout = %q, want it to contain %q", stdout, tt.stdout)
+			}
+			if !strings.Contains(stderr, tt.stderr) {
+				t.Errorf("stderr = %q, want it to contain %q", stderr, tt.stderr)
+			}
+		})
+	}
+}
+
+func TestRunRejectsMalformedEnvironment(t *testing.T) {
+	t.Setenv("AURORA_INTERVAL", "often")
+
+	code, _, stderr := runArgs(t, "check")
+	if code != 2 || !strings.Contains(stderr, "AURORA_INTERVAL") {
+		t.Fatalf("exit code = %d, stderr = %q; want 2 naming AURORA_INTERVAL", code, stderr)
+	}
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/config"
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/handlers"
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/health"
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/maintenance"
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/metrics"
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/notify"
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/redact"
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/store"
+)
+
+// shutdownTimeout bounds how long in-flight HTTP requests may take to
+// drain after SIGINT/SIGTERM.
+const shutdownTimeout = 10 * time.Second
+
+// configPoll is how often the config file is checked for changes.
+const configPoll = 2 * time.Second
+
+// runServe implements `aurora serve`: the dashboard plus the background
+// health checker, until SIGINT/SIGTERM.
+func runServe(args []string, stderr io.Writer) int {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	var cf checkFlags
+	if err := cf.register(flags); err != nil {
+		fmt.Fprintf(stderr, "aurora serve: %v\n", err)
+		return 2
+	}
+	addr := flags.String("addr", getEnv("AURORA_ADDR", ":8080"), "listen address (env AURORA_ADDR)")
+	templatesDir := flags.String("templates", getEnv("AURORA_TEMPLATES", "./web/templates"), "templates directory (env AURORA_TEMPLATES)")
+	staticDir := flags.String("static", getEnv("AURORA_STATIC", "./web/static"), "static files directory (env AURORA_STATIC)")
+	if err := flags.Parse(args); err != nil {
+		return parseExit(err)
+	}
+	// Secrets from the config are masked in everything logged.
+	redactor := redact.New()
+	if err := setupLogging(redactor.Writer(stderr), cf.logLevel); err != nil {
+		fmt.Fprintf(stderr, "aurora serve: %v\n", err)
+		return 2
+	}
+
+	// Cancelled on SIGINT/SIGTERM (Ctrl-C, systemd stop, docker stop).
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// A missing file starts an empty dashboard; an invalid one is fatal.
+	cfg, err := config.Load(cf.configPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		log.Printf("warning: could not load %s: %v", cf.configPath, err)
+		cfg = &config.Config{}
+	} else if err != nil {
+		log.Printf("error: %v", err)
+		return 1
+	}
+	redactor.Set(cfg.Secrets())
+
+	collector := metrics.New()
+
+	opts := []health.Option{
+		health.WithRedactor(redactor),
+		health.WithObserver(collector),
+		health.WithHistoryDepth(cfg.History.Depth),
+		health.WithWorkers(cfg.Scheduler.Workers),
+	}
+	if cfg.Scheduler.Jitter != nil {
+		opts = append(opts, health.WithJitter(*cfg.Scheduler.Jitter))
+	}
+
+	// Maintenance windows from the config plus silences set at runtime.
+	silences, err := maintenance.New(cfg.Maintenance)
+	if err != nil {
+		log.Printf("error: %v", err)
+		return 1
+	}
+	opts = append(opts, health.WithMaintenance(silences))
+
+	// Optional persistence so state survives restarts.
+	if cfg.Storage.Path != "" {
+		st, err := store.OpenFile(cfg.Storage.Path, store.WithResultTail(cfg.History.Depth))
+		if err != nil {
+			log.Printf("error: failed to open storage: %v", err)
+			return 1
+		}
+		defer st.Close()
+		opts = append(opts, health.WithStore(st, cfg.Storage.Retention))
+	}
+
+	// Services without their own interval/timeout use the config
+	// defaults, falling back to the flags.
+	checker := health.NewChecker(
+		cfg.Services,
+		cf.intervalFor(cfg),
+		cf.httpTimeout,
+		cf.tcpTimeout,
+		opts...,
+	)
+
+	// Status changes feed the transition counters and notifications.
+	events := checker.Subscribe()
+	defer checker.Unsubscribe(events)
+	go collector.Consume(events)
+
+	dispatcher, err := notify.New(cfg.Notifications,
+		notify.WithRedactor(redactor),
+		notify.WithStatus(func(name string) (health.Status, bool) {
+			res, ok := checker.Snapshot()[name]
+			return res.Status, ok
+		}),
+	)
+	if err != nil {
+		log.Printf("error: %v", err)
+		return 1
+	}
+	alerts := checker.Subscribe()
+	defer checker.Unsubscribe(alerts)
+	go dispatcher.Run(ctx, alerts)
+
+	checker.Start()
+
+	mux := http.NewServeMux()
+
+	// Static files (CSS, JS, images)
+	fileServer := http.FileServer(http.Dir(*staticDir))
+	mux.Handle("/static/", http.StripPrefix("/static/", fileServer))
+
+	// Dashboard handler with services + health checker.
+	dh, err := handlers.NewDashboardHandler(*templatesDir, cfg.Services, checker,
+		handlers.WithSilences(silences),
+	)
+	if err != nil {
+		checker.Stop()
+		log.Printf("error: failed to initialize dashboard handler: %v", err)
+		return 1
+	}
+
+	// Hot reload: swap the service set when the config file changes or
+	// on SIGHUP, along with maintenance windows. Other settings (storage,
+	// scheduler, ...) need a restart.
+	watcher := config.NewWatcher(cf.configPath, cfg, configPoll,
+		func(cfg *config.Config) {
+			redactor.Set(cfg.Secrets())
+			if err := silences.SetWindows(cfg.Maintenance.Windows); err != nil {
+				log.Printf("warning: keeping previous maintenance windows: %v", err)
+			}
+			// The dashboard first, so event streams woken by the
+			// checker render the new service set.
+			dh.SetServices(cfg.Services)
+			checker.UpdateServices(cfg.Services)
+			checker.RefreshMaintenance()
+			log.Printf("config reloaded: %d services", len(cfg.Services))
+		},
+		func(err error) {
+			log.Printf("warning: config reload rejected, keeping previous config: %v", err)
+		},
+	)
+	go watcher.Run(ctx)
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	go func() {
+		for range hup {
+			watcher.Trigger()
+		}
+	}()
+
+	mux.HandleFunc("/", dh.Dashboard)
+	mux.HandleFunc("/dashboard/partial", dh.DashboardPartial)
+	mux.HandleFunc("GET /dashboard/events", dh.Events)
+	mux.HandleFunc("/services/recheck", dh.RecheckService)
+	mux.HandleFunc("/services/history", dh.ServiceHistory)
+	mux.HandleFunc("GET /services/silence", dh.SilenceForm)
+	mux.HandleFunc("POST /services/silence", dh.Silence)
+	mux.HandleFunc("POST /services/unsilence", dh.Unsilence)
+
+	// JSON API for scripts and other dashboards.
+	mux.HandleFunc("GET /api/v1/services", dh.APIServices)
+	mux.HandleFunc("GET /api/v1/services/{name}", dh.APIService)
+	mux.HandleFunc("POST /api/v1/services/{name}/recheck", dh.APIRecheck)
+	mux.HandleFunc("GET /api/v1/summary", dh.APISummary)
+	mux.HandleFunc("GET /api/v1/silences", dh.APISilences)
+	mux.HandleFunc("POST /api/v1/silences", dh.APICreateSilence)
+	mux.HandleFunc("DELETE /api/v1/silences/{id}", dh.APIDeleteSilence)
+
+	// Send a test message to a notification target.
+	mux.Handle("POST /api/v1/notifications/{target}/test", dispatcher.TestHandler())
+
+	// Prometheus scrape endpoint.
+	mux.Handle("GET /metrics", collector.Handler(checker))
+
+	srv := &http.Server{
+		Addr:    *addr,
+		Handler: mux,
+	}
+	// Event streams never go idle; end them so Shutdown can finish.
+	srv.RegisterOnShutdown(dh.CloseStreams)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		log.Printf("Aurora Homelab listening on %s", *addr)
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	exitCode := 0
+	select {
+	case <-ctx.Done():
+		log.Printf("shutting down...")
+	case err := <-serveErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("error: server stopped: %v", err)
+			exitCode = 1
+		}
+	}
+
+	// Drain HTTP first so no request races the checker shutdown, then
+	// cancel in-flight checks and wait for them.
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("warning: http shutdown: %v", err)
+	}
+	checker.Stop()
+
+	log.Printf("Aurora Homelab stopped")
+	return exitCode
+}
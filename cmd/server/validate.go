@@ -0,0 +1,49 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/config"
+)
+
+// runValidate implements `aurora validate [config.yaml ...]`. It prints
+// every problem as file:line:col and returns a non-zero exit code if any
+// file is invalid, so it can gate config changes in CI.
+func runValidate(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: aurora validate [config.yaml ...]")
+	}
+	if err := fs.Parse(args); err != nil {
+		return parseExit(err)
+	}
+
+	paths := fs.Args()
+	if len(paths) == 0 {
+		paths = []string{"config.yaml"}
+	}
+
+	code := 0
+	for _, path := range paths {
+		cfg, err := config.Load(path)
+
+		var verr *config.ValidationError
+		switch {
+		case errors.As(err, &verr):
+			for _, p := range verr.Problems {
+				fmt.Fprintln(stderr, p)
+			}
+			code = 1
+		case err != nil:
+			fmt.Fprintf(stderr, "%s: %v\n", path, err)
+			code = 1
+		default:
+			fmt.Fprintf(stdout, "%s: OK (%d services)\n", path, len(cfg.Services))
+		}
+	}
+	return code
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateGoodConfig(t *testing.T) {
+	path := writeConfig(t, `services:
+  - name: Router
+    type: tcp
+    host: 10.0.0.1
+    port: 22
+`)
+
+	code, stdout, stderr := runArgs(t, "validate", path)
+	if code != 0 {
+		t.Fatalf("exit code = %d, want 0\nstderr: %s", code, stderr)
+	}
+	if want := path + ": OK (1 services)"; !strings.Contains(stdout, want) {
+		t.Fatalf("stdout = %q, want %q", stdout, want)
+	}
+}
+
+func TestValidateBadConfig(t *testing.T) {
+	path := writeConfig(t, `services:
+  - name: Router
+    type: smb
+`)
+
+	code, stdout, stderr := runArgs(t, "validate", path)
+	if code != 1 {
+		t.Fatalf("exit code = %d, want 1", code)
+	}
+	if stdout != "" {
+		t.Fatalf("stdout = %q, want nothing for an invalid config", stdout)
+	}
+	// Problems are reported as file:line:col so editors can jump to them.
+	if want := path + ":3:11:"; !strings.HasPrefix(stderr, want) {
+		t.Fatalf("stderr = %q, want it to start with %q", stderr, want)
+	}
+}
+
+func TestValidateMissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing.yaml")
+
+	code, stdout, stderr := runArgs(t, "validate", path)
+	if code != 1 {
+		t.Fatalf("exit code = %d, want 1", code)
+	}
+	if stdout != "" || !strings.HasPrefix(stderr, path+": ") {
+		t.Fatalf("stdout = %q, stderr = %q; want the error prefixed with the path", stdout, stderr)
+	}
+}
+
+func TestValidateChecksEveryFile(t *testing.T) {
+	good := writeConfig(t, "services: []\n")
+	bad := filepath.Join(t.TempDir(), "missing.yaml")
+
+	code, stdout, _ := runArgs(t, "validate", bad, good)
+	if code != 1 {
+		t.Fatalf("exit code = %d, want 1 when any file is invalid", code)
+	}
+	if !strings.Contains(stdout, good+": OK") {
+		t.Fatalf("stdout = %q, want the good file checked after the bad one", stdout)
+	}
+}
@@ -2,19 +2,110 @@ package config
 
 import (
 	"fmt"
+	"maps"
 	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/maintenance"
 	"github.com/cyber-mountain-man/aurora-homelab-go/internal/models"
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/notify"
 )
 
 // Config is the top-level configuration structure.
 type Config struct {
+	// Include lists glob patterns of additional files whose services
+	// are appended to Services, e.g. "services.d/*.yaml". Relative
+	// patterns are resolved against the directory of this file.
+	Include []string `yaml:"include,omitempty"`
+
+	Defaults  Defaults         `yaml:"defaults"`
+	History   History          `yaml:"history"`
+	Storage   Storage          `yaml:"storage"`
+	Scheduler Scheduler        `yaml:"scheduler"`
+	Services  []models.Service `yaml:"services"`
+
+	// Notifications configures where state changes are sent.
+	Notifications notify.Config `yaml:"notifications,omitempty"`
+
+	// Maintenance schedules windows during which services are shown as
+	// MAINTENANCE and do not alert.
+	Maintenance maintenance.Config `yaml:"maintenance,omitempty"`
+
+	secrets []string // interpolated secrets, see Secrets
+	watch   []string // files and globs this config was read from
+}
+
+// includeFile is the shape of a file pulled in by Include: services only,
+// so every default and setting lives in the main file.
+type includeFile struct {
 	Services []models.Service `yaml:"services"`
 }
 
+// History configures how much per-service check history is kept in memory.
+type History struct {
+	Depth int `yaml:"depth,omitempty"` // results per service (default 120)
+}
+
+// Defaults holds settings applied to every service that does not set
+// its own value. ByCategory and ByType override the global values for
+// matching services. Precedence, most specific first:
+//
+//	service > by_category > by_type > global defaults
+//
+// Headers are merged key by key with the same precedence; depends_on
+// is taken whole from the first level that sets it.
+type Defaults struct {
+	ServiceDefaults `yaml:",inline"`
+
+	ByType     map[string]ServiceDefaults `yaml:"by_type,omitempty"`     // keyed by service type
+	ByCategory map[string]ServiceDefaults `yaml:"by_category,omitempty"` // keyed by service category
+}
+
+// ServiceDefaults is one level of defaults. Retries and the thresholds
+// are pointers so that an explicit 0 overrides a less specific level.
+type ServiceDefaults struct {
+	Interval time.Duration `yaml:"interval,omitempty"`
+	Timeout  time.Duration `yaml:"timeout,omitempty"`
+	Retries  *int          `yaml:"retries,omitempty"`
+
+	FailureThreshold *int `yaml:"failure_threshold,omitempty"`
+	SuccessThreshold *int `yaml:"success_threshold,omitempty"`
+
+	Icon      string            `yaml:"icon,omitempty"`
+	Headers   map[string]string `yaml:"headers,omitempty"`
+	DependsOn []string          `yaml:"depends_on,omitempty"`
+}
+
+// Storage configures persistence of results across restarts.
+// Persistence is disabled when Path is empty.
+type Storage struct {
+	Path      string        `yaml:"path,omitempty"`      // e.g. "data/aurora.log"
+	Retention time.Duration `yaml:"retention,omitempty"` // default 720h (30 days)
+}
+
+// Scheduler controls how checks are spread out and how many run at once.
+// Jitter is a pointer so that "jitter: 0" can turn it off.
+type Scheduler struct {
+	Workers int      `yaml:"workers,omitempty"` // max concurrent checks (default 16)
+	Jitter  *float64 `yaml:"jitter,omitempty"`  // fraction of interval (default 0.1)
+}
+
 // Load reads a YAML config file from the given path and returns a Config.
+// Services from files matched by include are appended in glob order.
+//
+// Values may reference the environment as ${VAR} or ${VAR:-default},
+// and any value may be read from a file with {secret_file: path}
+// (Docker/Kubernetes secrets).
+//
+// The files are validated before the Config is returned: unknown keys,
+// bad values, duplicate service names (across all files), unknown check
+// types, missing host/port/url, dangling depends_on references and
+// depends_on cycles are all reported at once as a *ValidationError with file:line positions.
 func Load(path string) (*Config, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -22,9 +113,146 @@ func Load(path string) (*Config, error) {
 	}
 
 	var cfg Config
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
-		return nil, fmt.Errorf("unmarshal yaml: %w", err)
+	v := &validator{file: path}
+	root := v.parse(data, &cfg)
+
+	sources := serviceSources(path, root)
+	sources = append(sources, v.include(&cfg, root)...)
+	v.checkConfig(&cfg, root, sources)
+
+	if len(v.problems) > 0 {
+		sortProblems(v.problems, path)
+		return nil, &ValidationError{Problems: v.problems}
 	}
 
+	cfg.secrets = v.secrets
+	cfg.watch = append([]string{path}, v.watch...)
+	cfg.applyDefaults(explicitKeys(sources))
+
 	return &cfg, nil
 }
+
+// sortProblems orders problems by line within each file: the main file
+// first, then the others in the order they were first reported.
+func sortProblems(ps []Problem, mainFile string) {
+	order := map[string]int{mainFile: 0}
+	for _, p := range ps {
+		if _, ok := order[p.File]; !ok {
+			order[p.File] = len(order)
+		}
+	}
+	sort.SliceStable(ps, func(i, j int) bool {
+		if oi, oj := order[ps[i].File], order[ps[j].File]; oi != oj {
+			return oi < oj
+		}
+		return ps[i].Line < ps[j].Line
+	})
+}
+
+// Secrets returns the values that must not be shown anywhere: the
+// contents of every secret_file, and environment variables substituted
+// into headers, basic_auth, a password or token, a notification target
+// url, or the user:password part of a service url.
+func (c *Config) Secrets() []string {
+	return c.secrets
+}
+
+// applyDefaults fills unset per-service settings from Defaults, most
+// specific level first. set[i] holds the keys cfg.Services[i] sets
+// itself, so that an explicit "retries: 0" is kept; it may be shorter
+// than Services.
+func (c *Config) applyDefaults(set []map[string]bool) {
+	for i := range c.Services {
+		svc := &c.Services[i]
+		keys := make(map[string]bool)
+		if i < len(set) {
+			maps.Copy(keys, set[i])
+		}
+
+		typ := svc.Type
+		if typ == "" {
+			typ = "http"
+		}
+		for _, d := range []ServiceDefaults{
+			c.Defaults.ByCategory[svc.Category],
+			c.Defaults.ByType[typ],
+			c.Defaults.ServiceDefaults,
+		} {
+			d.applyTo(svc, keys)
+		}
+	}
+}
+
+// explicitKeys returns the keys set by each service entry in sources.
+func explicitKeys(sources []source) []map[string]bool {
+	out := make([]map[string]bool, len(sources))
+	for i, src := range sources {
+		if src.node == nil || src.node.Kind != yaml.MappingNode {
+			continue
+		}
+		out[i] = make(map[string]bool, len(src.node.Content)/2)
+		for j := 0; j+1 < len(src.node.Content); j += 2 {
+			out[i][src.node.Content[j].Value] = true
+		}
+	}
+	return out
+}
+
+// applyTo fills the fields of svc that are still unset from d. keys are
+// the yaml keys already set, by svc itself or a more specific level;
+// they are never overridden, even when 0, and applyTo adds the ones it
+// fills.
+func (d ServiceDefaults) applyTo(svc *models.Service, keys map[string]bool) {
+	if svc.Interval == 0 {
+		svc.Interval = d.Interval
+	}
+	if svc.Timeout == 0 {
+		svc.Timeout = d.Timeout
+	}
+	applyInt(&svc.Retries, d.Retries, keys, "retries")
+	applyInt(&svc.FailureThreshold, d.FailureThreshold, keys, "failure_threshold")
+	applyInt(&svc.SuccessThreshold, d.SuccessThreshold, keys, "success_threshold")
+	if svc.Icon == "" {
+		svc.Icon = d.Icon
+	}
+
+	if len(svc.DependsOn) == 0 && len(d.DependsOn) > 0 {
+		// A category default like "depends_on: [Proxmox]" must not make
+		// Proxmox depend on itself.
+		deps := slices.DeleteFunc(slices.Clone(d.DependsOn), func(dep string) bool {
+			return dep == svc.Name
+		})
+		if len(deps) > 0 {
+			svc.DependsOn = deps
+		}
+	}
+
+	for k, v := range d.Headers {
+		if hasHeader(svc.Headers, k) {
+			continue
+		}
+		if svc.Headers == nil {
+			svc.Headers = make(map[string]string, len(d.Headers))
+		}
+		svc.Headers[k] = v
+	}
+}
+
+// applyInt sets *dst to def unless key is already set or def is unset.
+func applyInt(dst, def *int, keys map[string]bool, key string) {
+	if keys[key] || def == nil {
+		return
+	}
+	*dst = *def
+	keys[key] = true
+}
+
+// hasHeader reports whether h sets key, ignoring case like HTTP does.
+func hasHeader(h map[string]string, key string) bool {
+	for k := range h {
+		if strings.EqualFold(k, key) {
+			return true
+		}
+	}
+	return false
+}
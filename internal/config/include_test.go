@@ -0,0 +1,216 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLoadIncludesAndDefaults(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "services.d"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dir, "services.d", "media.yaml"), `services:
+  - name: Plex
+    url: http://plex.lan
+    category: Media
+  - name: Jellyfin
+    url: http://jellyfin.lan
+    category: Media
+    interval: 5s
+    headers:
+      x-token: own
+`)
+	writeFile(t, filepath.Join(dir, "services.d", "infra.yaml"), `services:
+  - name: Proxmox
+    type: tcp
+    host: pve.lan
+    port: 8006
+    category: Infra
+`)
+
+	path := filepath.Join(dir, "config.yaml")
+	writeFile(t, path, `include:
+  - services.d/*.yaml
+defaults:
+  interval: 1m
+  timeout: 3s
+  icon: server
+  headers:
+    User-Agent: aurora
+  by_type:
+    tcp:
+      timeout: 1s
+      icon: network
+  by_category:
+    Media:
+      interval: 30s
+      icon: film
+      depends_on: [Proxmox]
+      headers:
+        X-Token: media
+    Infra:
+      depends_on: [Proxmox]
+services:
+  - name: Router
+    type: tcp
+    host: 10.0.0.1
+    port: 22
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	byName := make(map[string]int)
+	var names []string
+	for i, svc := range cfg.Services {
+		byName[svc.Name] = i
+		names = append(names, svc.Name)
+	}
+	// Main file first, then includes in glob order.
+	if want := []string{"Router", "Proxmox", "Plex", "Jellyfin"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("services = %v, want %v", names, want)
+	}
+
+	router := cfg.Services[byName["Router"]]
+	if router.Interval != time.Minute || router.Timeout != time.Second || router.Icon != "network" {
+		t.Errorf("Router: interval=%s timeout=%s icon=%q, want global interval, tcp timeout and icon", router.Interval, router.Timeout, router.Icon)
+	}
+
+	plex := cfg.Services[byName["Plex"]]
+	if plex.Interval != 30*time.Second || plex.Timeout != 3*time.Second || plex.Icon != "film" {
+		t.Errorf("Plex: interval=%s timeout=%s icon=%q, want category interval and icon, global timeout", plex.Interval, plex.Timeout, plex.Icon)
+	}
+	if want := map[string]string{"X-Token": "media", "User-Agent": "aurora"}; !reflect.DeepEqual(plex.Headers, want) {
+		t.Errorf("Plex headers = %v, want %v", plex.Headers, want)
+	}
+	if !reflect.DeepEqual(plex.DependsOn, []string{"Proxmox"}) {
+		t.Errorf("Plex depends_on = %v", plex.DependsOn)
+	}
+
+	jelly := cfg.Services[byName["Jellyfin"]]
+	if jelly.Interval != 5*time.Second {
+		t.Errorf("Jellyfin interval = %s, want its own 5s", jelly.Interval)
+	}
+	if want := map[string]string{"x-token": "own", "User-Agent": "aurora"}; !reflect.DeepEqual(jelly.Headers, want) {
+		t.Errorf("Jellyfin headers = %v, want %v (own header wins, case-insensitively)", jelly.Headers, want)
+	}
+
+	// A category default never makes a service depend on itself.
+	if pve := cfg.Services[byName["Proxmox"]]; len(pve.DependsOn) != 0 {
+		t.Errorf("Proxmox depends_on = %v, want none", pve.DependsOn)
+	}
+}
+
+func TestLoadKeepsExplicitZeroOverDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, path, `defaults:
+  retries: 3
+  failure_threshold: 2
+  by_category:
+    Lab:
+      retries: 0
+services:
+  - name: Router
+    type: tcp
+    host: 10.0.0.1
+    port: 22
+  - name: NAS
+    type: tcp
+    host: nas.lan
+    port: 445
+    retries: 0
+    failure_threshold: 0
+  - name: Pi
+    type: tcp
+    host: pi.lan
+    port: 22
+    category: Lab
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	for i, want := range []struct{ retries, failures int }{{3, 2}, {0, 0}, {0, 2}} {
+		svc := cfg.Services[i]
+		if svc.Retries != want.retries || svc.FailureThreshold != want.failures {
+			t.Errorf("%s: retries=%d failure_threshold=%d, want %d and %d",
+				svc.Name, svc.Retries, svc.FailureThreshold, want.retries, want.failures)
+		}
+	}
+}
+
+func TestLoadIncludeProblems(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.yaml"), `services:
+  - name: NAS
+    url: http://nas.lan
+`)
+	writeFile(t, filepath.Join(dir, "b.yaml"), `defaults:
+  interval: 1m
+services:
+  - name: NAS
+    url: http://nas2.lan
+`)
+
+	path := filepath.Join(dir, "config.yaml")
+	writeFile(t, path, `include: [a.yaml, b.yaml, missing.yaml]
+defaults:
+  by_type:
+    smtp:
+      interval: 1m
+  by_category:
+    Media:
+      depends_on: [Ghost]
+`)
+
+	_, err := Load(path)
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Load error = %v, want *ValidationError", err)
+	}
+
+	aPath := filepath.Join(dir, "a.yaml")
+	bPath := filepath.Join(dir, "b.yaml")
+	want := []Problem{
+		{File: path, Line: 1, Column: 27, Message: `include "missing.yaml": no such file`},
+		{File: path, Line: 4, Column: 5, Message: `defaults.by_type: unknown type "smtp" (want http, tcp, dns, ping or tls)`},
+		{File: path, Line: 8, Column: 20, Message: `defaults.by_category.Media.depends_on "Ghost": no such service`},
+		{File: bPath, Line: 1, Column: 1, Message: `unknown key "defaults"`},
+		{File: bPath, Line: 4, Column: 11, Message: `duplicate service name "NAS" (first defined in ` + aPath + `:2)`},
+	}
+	if !reflect.DeepEqual(verr.Problems, want) {
+		t.Fatalf("problems:\n%v\nwant:\n%v", verr.Problems, want)
+	}
+}
+
+func TestWatcherSeesNewIncludedFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	writeFile(t, path, "include: [\"*.svc.yaml\"]\n")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	reloads := make(chan *Config, 4)
+	w := NewWatcher(path, cfg, 10*time.Millisecond, func(c *Config) { reloads <- c }, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go w.Run(ctx)
+
+	writeFile(t, filepath.Join(dir, "nas.svc.yaml"), "services:\n  - name: NAS\n    url: http://nas.lan\n")
+	if got := recv(t, reloads); len(got.Services) != 1 {
+		t.Fatalf("reloaded %d services, want 1", len(got.Services))
+	}
+}
@@ -0,0 +1,219 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// sensitiveKeys mark subtrees whose interpolated values are treated as
+// secrets and redacted from results, logs and the dashboard. A service
+// url is not one: only what is substituted into its user:password part
+// is secret, so hosts and ports stay readable. Notification target urls
+// are, as webhooks carry their token in the path.
+var sensitiveKeys = map[string]bool{
+	"headers":    true,
+	"basic_auth": true,
+	"password":   true,
+	"token":      true,
+}
+
+// interpolate expands ${VAR} and ${VAR:-default} in every scalar value
+// under n and replaces {secret_file: path} mappings with the contents
+// of that file. Mapping keys are left alone. Secret file contents, and
+// environment values substituted under a sensitive key, are recorded
+// in v.secrets, as are values substituted into the userinfo of a url
+// (the whole url when secretURLs is set).
+func (v *validator) interpolate(n *yaml.Node, sensitive, secretURLs bool) {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		if values := v.expandScalar(n); sensitive {
+			v.secrets = append(v.secrets, values...)
+		}
+
+	case yaml.MappingNode:
+		if path := mappingValue(n, "secret_file"); path != nil {
+			v.loadSecretFile(n, path)
+			return
+		}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, val := n.Content[i], n.Content[i+1]
+			if key.Value == "url" && !sensitive && !secretURLs && val.Kind == yaml.ScalarNode {
+				values := v.expandScalar(val)
+				v.secrets = append(v.secrets, userinfoValues(val.Value, values)...)
+				continue
+			}
+			v.interpolate(val,
+				sensitive || sensitiveKeys[key.Value] || (secretURLs && key.Value == "url"),
+				secretURLs || key.Value == "notifications")
+		}
+
+	case yaml.SequenceNode:
+		for _, item := range n.Content {
+			v.interpolate(item, sensitive, secretURLs)
+		}
+	}
+}
+
+// expandScalar interpolates a single scalar node in place and returns
+// the environment values it substituted.
+func (v *validator) expandScalar(n *yaml.Node) []string {
+	if !strings.Contains(n.Value, "$") {
+		return nil
+	}
+
+	out, values, err := expandEnv(n.Value)
+	if err != "" {
+		v.addf(n, "%s", err)
+		return nil
+	}
+	if out == n.Value {
+		return nil
+	}
+
+	n.Value = out
+	// Let an unquoted "${PORT}" resolve as a number, bool, etc.
+	if n.Style == 0 {
+		n.Tag = ""
+	}
+	return values
+}
+
+// userinfoValues returns those of values that ended up in the
+// "user:password@" part of the expanded url u.
+func userinfoValues(u string, values []string) []string {
+	_, rest, ok := strings.Cut(u, "://")
+	if !ok || len(values) == 0 {
+		return nil
+	}
+	if i := strings.IndexAny(rest, "/?#"); i >= 0 {
+		rest = rest[:i]
+	}
+	at := strings.LastIndex(rest, "@")
+	if at < 0 {
+		return nil
+	}
+
+	var out []string
+	for _, val := range values {
+		if strings.Contains(rest[:at], val) {
+			out = append(out, val)
+		}
+	}
+	return out
+}
+
+// expandEnv expands ${VAR} and ${VAR:-default} in s. "$${" is a
+// literal "${"; any other "$" is kept as is, so regular expressions
+// need no escaping. It returns the environment values that were
+// substituted, or a message describing the first problem.
+func expandEnv(s string) (out string, values []string, problem string) {
+	var b strings.Builder
+	for {
+		i := strings.Index(s, "$")
+		if i < 0 {
+			b.WriteString(s)
+			return b.String(), values, ""
+		}
+		b.WriteString(s[:i])
+		s = s[i:]
+
+		switch {
+		case strings.HasPrefix(s, "$${"):
+			b.WriteString("${")
+			s = s[3:]
+			continue
+		case !strings.HasPrefix(s, "${"):
+			b.WriteByte('$')
+			s = s[1:]
+			continue
+		}
+
+		end := strings.IndexByte(s, '}')
+		if end < 0 {
+			return "", nil, "unterminated ${ in " + quote(s)
+		}
+		expr := s[2:end]
+		s = s[end+1:]
+
+		name, def, hasDef := strings.Cut(expr, ":-")
+		if !validEnvName(name) {
+			return "", nil, "invalid variable name " + quote(name)
+		}
+
+		val, ok := os.LookupEnv(name)
+		switch {
+		case ok && val != "":
+			values = append(values, val)
+		case hasDef:
+			val = def
+		case ok:
+			// Set but empty, no default: substitute the empty string.
+		default:
+			return "", nil, "environment variable " + name + " is not set (use ${" + name + ":-default} for a fallback)"
+		}
+		b.WriteString(val)
+	}
+}
+
+func validEnvName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r == '_', r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func quote(s string) string {
+	return `"` + s + `"`
+}
+
+// loadSecretFile replaces mapping n ({secret_file: path}) with a string
+// scalar holding the file's contents, minus trailing newlines. Relative
+// paths are resolved against the config file's directory.
+func (v *validator) loadSecretFile(n, pathNode *yaml.Node) {
+	if len(n.Content) != 2 {
+		v.addf(n, "secret_file cannot be combined with other keys")
+		return
+	}
+
+	path, _, problem := expandEnv(pathNode.Value)
+	if problem != "" {
+		v.addf(pathNode, "%s", problem)
+		return
+	}
+	if path == "" {
+		v.addf(pathNode, "secret_file path is empty")
+		return
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(filepath.Dir(v.file), path)
+	}
+
+	v.watch = append(v.watch, path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		v.addf(pathNode, "secret_file: %v", err)
+		return
+	}
+	secret := strings.TrimRight(string(data), "\r\n")
+
+	*n = yaml.Node{
+		Kind:   yaml.ScalarNode,
+		Tag:    "!!str",
+		Value:  secret,
+		Line:   n.Line,
+		Column: n.Column,
+	}
+	v.secrets = append(v.secrets, secret)
+}
@@ -0,0 +1,588 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/health"
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/models"
+)
+
+// knownType reports whether the health package has a backend for
+// check type typ; an empty type defaults to http.
+func knownType(typ string) bool {
+	return typ == "" || slices.Contains(health.CheckTypes, typ)
+}
+
+// Problem is a single configuration error with its position in the file.
+// Line and Column are 1-based; zero means the position is unknown.
+type Problem struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (p Problem) String() string {
+	switch {
+	case p.Line > 0 && p.Column > 0:
+		return fmt.Sprintf("%s:%d:%d: %s", p.File, p.Line, p.Column, p.Message)
+	case p.Line > 0:
+		return fmt.Sprintf("%s:%d: %s", p.File, p.Line, p.Message)
+	default:
+		return fmt.Sprintf("%s: %s", p.File, p.Message)
+	}
+}
+
+// ValidationError lists every problem found in a config file.
+type ValidationError struct {
+	Problems []Problem
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Problems) == 1 {
+		return "invalid config: " + e.Problems[0].String()
+	}
+	lines := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		lines[i] = "  " + p.String()
+	}
+	return fmt.Sprintf("invalid config: %d problems:\n%s", len(e.Problems), strings.Join(lines, "\n"))
+}
+
+// validator collects problems for one file.
+type validator struct {
+	file     string
+	problems []Problem
+	secrets  []string // values to redact, see interpolate
+	watch    []string // included files and secret files, see Watcher
+}
+
+// source locates a service entry: its file and YAML node.
+type source struct {
+	file string
+	node *yaml.Node
+}
+
+func (v *validator) addf(n *yaml.Node, format string, args ...any) {
+	p := Problem{File: v.file, Message: fmt.Sprintf(format, args...)}
+	if n != nil {
+		p.Line, p.Column = n.Line, n.Column
+	}
+	v.problems = append(v.problems, p)
+}
+
+// parse decodes data into out (a struct pointer), reporting YAML syntax
+// errors, type errors and unknown keys as problems. It returns the
+// document's root mapping (nil if the document could not be parsed).
+func (v *validator) parse(data []byte, out any) *yaml.Node {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		v.addYAMLError(err)
+		return nil
+	}
+	if len(doc.Content) == 0 {
+		return nil // empty file
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		v.addf(root, "top level must be a mapping")
+		return nil
+	}
+
+	v.interpolate(root, false, false)
+	v.checkKeys(root, reflect.TypeOf(out), "")
+
+	if err := root.Decode(out); err != nil {
+		v.addYAMLError(err)
+	}
+	return root
+}
+
+// addYAMLError converts a yaml.v3 error ("yaml: line 3: ...") into
+// problems, keeping the line number when there is one.
+func (v *validator) addYAMLError(err error) {
+	var msgs []string
+	var te *yaml.TypeError
+	if errors.As(err, &te) {
+		msgs = te.Errors
+	} else {
+		msgs = []string{err.Error()}
+	}
+
+	for _, msg := range msgs {
+		msg = strings.TrimPrefix(msg, "yaml: ")
+		p := Problem{File: v.file, Message: msg}
+		if rest, ok := strings.CutPrefix(msg, "line "); ok {
+			if num, tail, ok := strings.Cut(rest, ": "); ok {
+				if line, err := strconv.Atoi(num); err == nil {
+					p.Line, p.Message = line, tail
+				}
+			}
+		}
+		v.problems = append(v.problems, p)
+	}
+}
+
+// checkKeys reports mapping keys that do not correspond to a yaml-tagged
+// field of t, recursing into nested structs and slices of structs.
+func (v *validator) checkKeys(n *yaml.Node, t reflect.Type, path string) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t.Kind() == reflect.Struct && n.Kind == yaml.MappingNode:
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, val := n.Content[i], n.Content[i+1]
+			ft, ok := fields[key.Value]
+			if !ok {
+				v.addf(key, "unknown key %q%s", key.Value, inPath(path))
+				continue
+			}
+			v.checkKeys(val, ft, joinPath(path, key.Value))
+		}
+	case t.Kind() == reflect.Slice && n.Kind == yaml.SequenceNode:
+		for i, item := range n.Content {
+			v.checkKeys(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))
+		}
+	case t.Kind() == reflect.Map && n.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			v.checkKeys(n.Content[i+1], t.Elem(), joinPath(path, n.Content[i].Value))
+		}
+	}
+}
+
+// yamlFields maps yaml keys of struct t to their field types.
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		if opts == "inline" && f.Type.Kind() == reflect.Struct {
+			for k, ft := range yamlFields(f.Type) {
+				fields[k] = ft
+			}
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func inPath(path string) string {
+	if path == "" {
+		return ""
+	}
+	return " in " + path
+}
+
+// mappingValue returns the value node for key in mapping n, or nil.
+func mappingValue(n *yaml.Node, key string) *yaml.Node {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// at returns the node for key in n, falling back to n itself so that
+// problems with a missing key point at the enclosing mapping.
+func at(n *yaml.Node, key string) *yaml.Node {
+	if v := mappingValue(n, key); v != nil {
+		return v
+	}
+	return n
+}
+
+// serviceSources returns the source of every entry under root's
+// services key.
+func serviceSources(file string, root *yaml.Node) []source {
+	seq := mappingValue(root, "services")
+	if seq == nil || seq.Kind != yaml.SequenceNode {
+		return nil
+	}
+	out := make([]source, len(seq.Content))
+	for i, item := range seq.Content {
+		out[i] = source{file: file, node: item}
+	}
+	return out
+}
+
+// include loads the files matched by cfg.Include, appends their
+// services to cfg.Services and returns their sources.
+func (v *validator) include(cfg *Config, root *yaml.Node) []source {
+	mainFile := v.file
+	defer func() { v.file = mainFile }()
+
+	patterns := mappingValue(root, "include")
+	loaded := map[string]bool{filepath.Clean(mainFile): true}
+
+	var sources []source
+	for i, pattern := range cfg.Include {
+		pn := patterns
+		if pn != nil && pn.Kind == yaml.SequenceNode && i < len(pn.Content) {
+			pn = pn.Content[i]
+		}
+
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(filepath.Dir(mainFile), pattern)
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			v.addf(pn, "include %q: %v", cfg.Include[i], err)
+			continue
+		}
+		if len(matches) == 0 && !strings.ContainsAny(pattern, `*?[\`) {
+			v.addf(pn, "include %q: no such file", cfg.Include[i])
+			continue
+		}
+		v.watch = append(v.watch, pattern)
+
+		for _, file := range matches {
+			if loaded[filepath.Clean(file)] {
+				continue
+			}
+			loaded[filepath.Clean(file)] = true
+
+			data, err := os.ReadFile(file)
+			if err != nil {
+				v.addf(pn, "include %q: %v", cfg.Include[i], err)
+				continue
+			}
+
+			v.file = file
+			var inc includeFile
+			incRoot := v.parse(data, &inc)
+			cfg.Services = append(cfg.Services, inc.Services...)
+			sources = append(sources, serviceSources(file, incRoot)...)
+			v.file = mainFile
+		}
+	}
+	return sources
+}
+
+// checkConfig validates settings that decode fine but make no sense.
+// root may be nil (e.g. for an empty file), in which case positions
+// are omitted. sources[i] locates cfg.Services[i].
+func (v *validator) checkConfig(cfg *Config, root *yaml.Node, sources []source) {
+	if cfg.History.Depth < 0 {
+		v.addf(at(mappingValue(root, "history"), "depth"), "history.depth must not be negative")
+	}
+	if cfg.Storage.Retention < 0 {
+		v.addf(at(mappingValue(root, "storage"), "retention"), "storage.retention must not be negative")
+	}
+
+	sched := mappingValue(root, "scheduler")
+	if cfg.Scheduler.Workers < 0 {
+		v.addf(at(sched, "workers"), "scheduler.workers must not be negative")
+	}
+	if j := cfg.Scheduler.Jitter; j != nil && (*j < 0 || *j > 1) {
+		v.addf(at(sched, "jitter"), "scheduler.jitter must be between 0 and 1")
+	}
+
+	sourceFor := func(i int) source {
+		if i < len(sources) {
+			return sources[i]
+		}
+		return source{file: v.file}
+	}
+
+	// First pass: names, so depends_on can be checked against all of them.
+	mainFile := v.file
+	seen := make(map[string]source, len(cfg.Services))
+	for i, svc := range cfg.Services {
+		src := sourceFor(i)
+		v.file = src.file
+		if strings.TrimSpace(svc.Name) == "" {
+			v.addf(src.node, "services[%d]: name is required", i)
+			continue
+		}
+		if first, dup := seen[svc.Name]; dup {
+			v.addf(at(src.node, "name"), "duplicate service name %q (first defined %s)", svc.Name, first.position(src.file))
+			continue
+		}
+		seen[svc.Name] = source{file: src.file, node: at(src.node, "name")}
+	}
+
+	for i, svc := range cfg.Services {
+		src := sourceFor(i)
+		v.file = src.file
+		v.checkService(i, svc, src.node, seen)
+	}
+	v.checkDependencyCycles(cfg, sourceFor)
+	v.file = mainFile
+
+	v.checkDefaults(cfg.Defaults, mappingValue(root, "defaults"), seen)
+	notifications := mappingValue(root, "notifications")
+	for _, p := range cfg.Notifications.Validate() {
+		v.addProblem(notifications, p.Field, p.Message, p.First)
+	}
+	maint := mappingValue(root, "maintenance")
+	for _, p := range cfg.Maintenance.Validate(cfg.Services) {
+		v.addProblem(maint, p.Field, p.Message, p.First)
+	}
+}
+
+// checkDependencyCycles reports depends_on cycles, including ones made
+// through defaults: root-cause attribution needs the dependencies to
+// form a DAG.
+func (v *validator) checkDependencyCycles(cfg *Config, sourceFor func(int) source) {
+	graph := make([]models.Service, len(cfg.Services))
+	index := make(map[string]int, len(cfg.Services))
+	for i, svc := range cfg.Services {
+		graph[i] = models.Service{Name: svc.Name, Type: svc.Type, Category: svc.Category, DependsOn: svc.DependsOn}
+		if _, ok := index[svc.Name]; !ok {
+			index[svc.Name] = i
+		}
+	}
+	(&Config{Services: graph, Defaults: cfg.Defaults}).applyDefaults(nil)
+
+	for _, cycle := range health.DependencyCycles(graph) {
+		if len(cycle) < 3 {
+			continue // depends on itself, reported by checkService
+		}
+		i := index[cycle[0]]
+		src := sourceFor(i)
+		v.file = src.file
+		n := src.node
+		if j := slices.Index(cfg.Services[i].DependsOn, cycle[1]); j >= 0 {
+			n = seqItem(mappingValue(src.node, "depends_on"), j)
+		}
+		v.addf(n, "%s: depends_on cycle %s", cycle[0], strings.Join(cycle, " -> "))
+	}
+}
+
+// addProblem reports a problem found by the notify or maintenance
+// package at field (e.g. "targets[2].url") below n, the node of its
+// section. first, if set, is the field of the definition it duplicates.
+func (v *validator) addProblem(n *yaml.Node, field, msg, first string) {
+	if first != "" {
+		if fn := fieldNode(n, first); fn != nil {
+			msg += fmt.Sprintf(" (first defined on line %d)", fn.Line)
+		}
+	}
+	v.addf(fieldNode(n, field), "%s", msg)
+}
+
+// fieldNode returns the node at field, a path of keys and indexes such
+// as "routes[0].escalate.targets[1]", below n. Like at, it falls back
+// to the deepest node that exists.
+func fieldNode(n *yaml.Node, field string) *yaml.Node {
+	if field == "" {
+		return n
+	}
+	for _, part := range strings.Split(field, ".") {
+		key, index, _ := strings.Cut(part, "[")
+		next := mappingValue(n, key)
+		if next == nil {
+			return n
+		}
+		n = next
+		if index != "" {
+			i, err := strconv.Atoi(strings.TrimSuffix(index, "]"))
+			if err != nil || n.Kind != yaml.SequenceNode || i >= len(n.Content) {
+				return n
+			}
+			n = n.Content[i]
+		}
+	}
+	return n
+}
+
+// position describes where s is, omitting the file name when it is
+// the same as the file being reported on.
+func (s source) position(file string) string {
+	line := 0
+	if s.node != nil {
+		line = s.node.Line
+	}
+	if s.file == file {
+		return fmt.Sprintf("on line %d", line)
+	}
+	return fmt.Sprintf("in %s:%d", s.file, line)
+}
+
+// checkDefaults validates the global defaults and every by_type and
+// by_category block.
+func (v *validator) checkDefaults(d Defaults, n *yaml.Node, names map[string]source) {
+	v.checkServiceDefaults(d.ServiceDefaults, n, "defaults", names)
+
+	byType := mappingValue(n, "by_type")
+	for typ, td := range d.ByType {
+		key, val := mappingEntry(byType, typ)
+		if typ == "" || !knownType(typ) {
+			v.addf(key, "defaults.by_type: unknown type %q (want http, tcp, dns, ping or tls)", typ)
+		}
+		v.checkServiceDefaults(td, val, "defaults.by_type."+typ, names)
+	}
+
+	byCategory := mappingValue(n, "by_category")
+	for cat, cd := range d.ByCategory {
+		_, val := mappingEntry(byCategory, cat)
+		v.checkServiceDefaults(cd, val, "defaults.by_category."+cat, names)
+	}
+}
+
+// checkServiceDefaults validates one level of defaults.
+func (v *validator) checkServiceDefaults(d ServiceDefaults, n *yaml.Node, label string, names map[string]source) {
+	if d.Interval < 0 {
+		v.addf(at(n, "interval"), "%s.interval must not be negative", label)
+	}
+	if d.Timeout < 0 {
+		v.addf(at(n, "timeout"), "%s.timeout must not be negative", label)
+	}
+	if d.Retries != nil && *d.Retries < 0 {
+		v.addf(at(n, "retries"), "%s.retries must not be negative", label)
+	}
+	if d.FailureThreshold != nil && *d.FailureThreshold < 0 {
+		v.addf(at(n, "failure_threshold"), "%s.failure_threshold must not be negative", label)
+	}
+	if d.SuccessThreshold != nil && *d.SuccessThreshold < 0 {
+		v.addf(at(n, "success_threshold"), "%s.success_threshold must not be negative", label)
+	}
+
+	deps := mappingValue(n, "depends_on")
+	for j, dep := range d.DependsOn {
+		if _, ok := names[dep]; !ok {
+			v.addf(seqItem(deps, j), "%s.depends_on %q: no such service", label, dep)
+		}
+	}
+}
+
+// mappingEntry returns the key and value nodes for key in mapping n,
+// falling back to n for both if it is missing.
+func mappingEntry(n *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	if n != nil && n.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if n.Content[i].Value == key {
+				return n.Content[i], n.Content[i+1]
+			}
+		}
+	}
+	return n, n
+}
+
+// seqItem returns item i of sequence n, falling back to n itself.
+func seqItem(n *yaml.Node, i int) *yaml.Node {
+	if n != nil && n.Kind == yaml.SequenceNode && i < len(n.Content) {
+		return n.Content[i]
+	}
+	return n
+}
+
+// checkService validates one service entry.
+func (v *validator) checkService(i int, svc models.Service, n *yaml.Node, names map[string]source) {
+	label := svc.Name
+	if label == "" {
+		label = fmt.Sprintf("services[%d]", i)
+	}
+
+	if !knownType(svc.Type) {
+		v.addf(at(n, "type"), "%s: unknown type %q (want http, tcp, dns, ping or tls)", label, svc.Type)
+	}
+
+	switch svc.Type {
+	case "", "http":
+		if svc.URL == "" {
+			v.addf(n, "%s: url is required for http checks", label)
+		} else if u, err := url.Parse(svc.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			v.addf(at(n, "url"), "%s: url %q must be an absolute http:// or https:// URL", label, svc.URL)
+		}
+	case "tcp":
+		if svc.Host == "" {
+			v.addf(n, "%s: host is required for tcp checks", label)
+		}
+		if svc.Port == 0 {
+			v.addf(n, "%s: port is required for tcp checks", label)
+		}
+	case "dns", "ping", "tls":
+		if svc.Host == "" {
+			v.addf(n, "%s: host is required for %s checks", label, svc.Type)
+		}
+	}
+
+	if svc.Port < 0 || svc.Port > 65535 {
+		v.addf(at(n, "port"), "%s: port %d out of range (1-65535)", label, svc.Port)
+	}
+
+	if svc.Interval < 0 {
+		v.addf(at(n, "interval"), "%s: interval must not be negative", label)
+	}
+	if svc.Timeout < 0 {
+		v.addf(at(n, "timeout"), "%s: timeout must not be negative", label)
+	}
+	if svc.Retries < 0 {
+		v.addf(at(n, "retries"), "%s: retries must not be negative", label)
+	}
+	if svc.FailureThreshold < 0 {
+		v.addf(at(n, "failure_threshold"), "%s: failure_threshold must not be negative", label)
+	}
+	if svc.SuccessThreshold < 0 {
+		v.addf(at(n, "success_threshold"), "%s: success_threshold must not be negative", label)
+	}
+
+	expect := mappingValue(n, "expect")
+	for _, code := range svc.Expect.Status {
+		if code < 100 || code > 599 {
+			v.addf(at(expect, "status"), "%s: expect.status %d is not an HTTP status code", label, code)
+		}
+	}
+	if svc.Expect.BodyRegex != "" {
+		if _, err := regexp.Compile(svc.Expect.BodyRegex); err != nil {
+			v.addf(at(expect, "body_regex"), "%s: expect.body_regex: %v", label, err)
+		}
+	}
+	if svc.Expect.JSONEquals != "" && svc.Expect.JSONPath == "" {
+		v.addf(at(expect, "json_equals"), "%s: expect.json_equals needs expect.json_path", label)
+	}
+	if svc.Expect.MaxBodyBytes < 0 {
+		v.addf(at(expect, "max_body_bytes"), "%s: expect.max_body_bytes must not be negative", label)
+	}
+
+	if svc.TLS.WarnDays < 0 {
+		v.addf(at(mappingValue(n, "tls"), "warn_days"), "%s: tls.warn_days must not be negative", label)
+	}
+
+	deps := mappingValue(n, "depends_on")
+	for j, dep := range svc.DependsOn {
+		dn := seqItem(deps, j)
+		if dep == svc.Name {
+			v.addf(dn, "%s: depends_on refers to itself", label)
+		} else if _, ok := names[dep]; !ok {
+			v.addf(dn, "%s: depends_on %q: no such service", label, dep)
+		}
+	}
+}
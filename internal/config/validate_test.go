@@ -0,0 +1,297 @@
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func loadString(t *testing.T, data string) (*Config, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, path, data)
+	return Load(path)
+}
+
+func problems(t *testing.T, data string) []string {
+	t.Helper()
+	_, err := loadString(t, data)
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Load error = %v, want *ValidationError", err)
+	}
+	out := make([]string, len(verr.Problems))
+	for i, p := range verr.Problems {
+		// Drop the temp dir so tests can match on "config.yaml:L:C: msg".
+		p.File = filepath.Base(p.File)
+		out[i] = p.String()
+	}
+	return out
+}
+
+func TestLoadValidConfig(t *testing.T) {
+	cfg, err := loadString(t, `
+defaults:
+  interval: 1m
+services:
+  - name: Router
+    type: tcp
+    host: 10.0.0.1
+    port: 22
+  - name: Plex
+    url: http://plex.lan:32400
+    depends_on: [Router]
+    expect:
+      status: [200]
+      body_regex: "ok|ready"
+`)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(cfg.Services) != 2 || cfg.Services[1].Interval.String() != "1m0s" {
+		t.Fatalf("unexpected config: %+v", cfg.Services)
+	}
+}
+
+func TestLoadReportsAllProblemsWithPositions(t *testing.T) {
+	got := problems(t, `defaults:
+  intervall: 3s
+services:
+  - name: NAS
+    type: tcp
+    host: nas
+  - name: NAS
+    url: ftp://nas
+    depends_on: [Ghost]
+  - name: Mail
+    type: smtp
+    expect:
+      body_regex: "("
+`)
+
+	want := []string{
+		`config.yaml:2:3: unknown key "intervall" in defaults`,
+		`config.yaml:4:5: NAS: port is required for tcp checks`,
+		`config.yaml:7:11: duplicate service name "NAS" (first defined on line 4)`,
+		`config.yaml:8:10: NAS: url "ftp://nas" must be an absolute http:// or https:// URL`,
+		`config.yaml:9:18: NAS: depends_on "Ghost": no such service`,
+		`config.yaml:11:11: Mail: unknown type "smtp" (want http, tcp, dns, ping or tls)`,
+		"config.yaml:13:19: Mail: expect.body_regex: error parsing regexp: missing closing ): `(`",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("problems:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestLoadReportsTypeAndSyntaxErrors(t *testing.T) {
+	got := problems(t, "services:\n  - name: A\n    url: http://a\n    interval: soon\n")
+	if len(got) != 1 || !strings.HasPrefix(got[0], "config.yaml:4: cannot unmarshal") {
+		t.Fatalf("type error problems = %q", got)
+	}
+
+	got = problems(t, "services: [\n")
+	if len(got) != 1 || !strings.HasPrefix(got[0], "config.yaml:") {
+		t.Fatalf("syntax error problems = %q", got)
+	}
+}
+
+func TestLoadRejectsUnknownNestedKeys(t *testing.T) {
+	got := problems(t, `scheduler:
+  jitter: 1.5
+services:
+  - name: Site
+    url: https://example.com
+    expect:
+      stauts: [200]
+    tls:
+      warn_dayz: 7
+`)
+	want := []string{
+		`config.yaml:2:11: scheduler.jitter must be between 0 and 1`,
+		`config.yaml:7:7: unknown key "stauts" in services[0].expect`,
+		`config.yaml:9:7: unknown key "warn_dayz" in services[0].tls`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("problems:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestLoadValidatesNotifications(t *testing.T) {
+	got := problems(t, `notifications:
+  targets:
+    - name: pager
+      url: https://hooks.example/pager
+      body: '{"text": {{json .Text}'
+    - name: pager
+      type: carrier-pigeon
+    - url: ftp://hooks
+      method: DELETE
+      retries: -1
+services: []
+`)
+
+	want := []string{
+		`config.yaml:5:13: pager: body: template: pager:1: bad character U+007D '}'`,
+		`config.yaml:6:13: duplicate notification target "pager" (first defined on line 3)`,
+		`config.yaml:7:13: pager: unknown notification type "carrier-pigeon" (want webhook, ntfy, gotify, discord, slack, telegram or smtp)`,
+		`config.yaml:8:7: notifications.targets[2]: name is required`,
+		`config.yaml:8:12: notifications.targets[2]: url must be an absolute http:// or https:// URL`,
+		`config.yaml:9:15: notifications.targets[2]: unsupported method "DELETE" (want POST, PUT, PATCH or GET)`,
+		`config.yaml:10:16: notifications.targets[2]: retries must not be negative`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("problems:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestLoadValidatesNativeNotifiers(t *testing.T) {
+	got := problems(t, `notifications:
+  targets:
+    - name: phone
+      type: ntfy
+      priority: 9
+    - name: bot
+      type: telegram
+      chat_id: "42"
+    - name: chat
+      type: slack
+      body: "{{.Text}}"
+services: []
+`)
+
+	want := []string{
+		`config.yaml:3:7: phone: topic is required for ntfy targets`,
+		`config.yaml:5:17: phone: priority must be between 1 and 5 for ntfy`,
+		`config.yaml:6:7: bot: token is required for telegram targets`,
+		`config.yaml:9:7: chat: url is required for slack targets`,
+		`config.yaml:9:7: chat: method and body only apply to webhook targets`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("problems:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestLoadValidatesSMTPTargets(t *testing.T) {
+	got := problems(t, `notifications:
+  targets:
+    - name: mail
+      type: smtp
+      tls_mode: ssl
+      from: aurora
+      to: [ops@example.com, "not an address"]
+    - name: hook
+      url: https://example.com/hook
+      batch_window: 1m
+services: []
+`)
+
+	want := []string{
+		`config.yaml:3:7: mail: host is required for smtp targets`,
+		`config.yaml:5:17: mail: unknown tls_mode "ssl" (want starttls, implicit or none)`,
+		`config.yaml:6:13: mail: from "aurora": mail: missing '@' or angle-addr`,
+		`config.yaml:7:29: mail: to "not an address": mail: no angle-addr`,
+		`config.yaml:10:21: hook: batch_window only applies to smtp targets`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("problems:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestLoadValidatesNotificationRoutes(t *testing.T) {
+	got := problems(t, `notifications:
+  targets:
+    - name: chat
+      url: https://example.com/hook
+  routes:
+    - match: {service: "plex[", reason: slow, type: smb, time: "22:00"}
+      targets: [chat, pager]
+    - escalate:
+        targets: [chat]
+      repeat: -1m
+services: []
+`)
+
+	want := []string{
+		`config.yaml:6:24: notifications.routes[0]: service "plex[": syntax error in pattern`,
+		`config.yaml:6:53: notifications.routes[0]: unknown type "smb"`,
+		`config.yaml:6:41: notifications.routes[0]: unknown reason "slow" (want timeout, dns, conn, permission, tls, http, unknown or other)`,
+		`config.yaml:6:64: notifications.routes[0]: time "22:00": want HH:MM-HH:MM`,
+		`config.yaml:7:23: notifications.routes[0]: unknown notification target "pager"`,
+		`config.yaml:8:7: notifications.routes[1]: targets is required`,
+		`config.yaml:9:9: notifications.routes[1]: escalate.after must be positive`,
+		`config.yaml:10:15: notifications.routes[1]: repeat must not be negative`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("problems:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestLoadValidatesMaintenance(t *testing.T) {
+	got := problems(t, `services:
+  - name: Proxmox
+    url: https://pve.lan:8006
+    category: Infrastructure
+maintenance:
+  windows:
+    - name: patch night
+      categories: [infrastructure, media]
+      schedule: "0 3 * * funday"
+      duration: 2h
+    - name: patch night
+      services: [Proxmox, Plex]
+      schedule: "0 3 * * sun"
+      start: "2026-11-01 20:00"
+      duration: 1h
+    - name: move
+      services: [Proxmox]
+      start: "next tuesday"
+    - categories: [infrastructure]
+`)
+
+	want := []string{
+		`config.yaml:8:36: patch night: categories "media": no service has this category`,
+		`config.yaml:9:17: patch night: cron "0 3 * * funday": bad value "funday" (want 0-7)`,
+		`config.yaml:11:13: duplicate maintenance window "patch night" (first defined on line 7)`,
+		`config.yaml:11:7: patch night: schedule and start are mutually exclusive`,
+		`config.yaml:12:27: patch night: services "Plex": no such service`,
+		`config.yaml:16:7: move: duration must be positive`,
+		`config.yaml:18:14: move: start "next tuesday": want YYYY-MM-DD HH:MM or RFC 3339`,
+		`config.yaml:19:7: maintenance.windows[3]: name is required`,
+		`config.yaml:19:7: maintenance.windows[3]: schedule (recurring) or start (one-off) is required`,
+		`config.yaml:19:7: maintenance.windows[3]: duration must be positive`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("problems:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestLoadRejectsDependencyCycles(t *testing.T) {
+	got := problems(t, `defaults:
+  by_category:
+    Storage:
+      depends_on: [Plex]
+services:
+  - name: Proxmox
+    type: tcp
+    host: pve
+    port: 8006
+    depends_on: [Proxmox]
+  - name: NAS
+    type: tcp
+    host: nas
+    port: 22
+    category: Storage
+  - name: Plex
+    url: http://plex.lan:32400
+    depends_on: [Proxmox, NAS]
+`)
+
+	want := []string{
+		`config.yaml:10:18: Proxmox: depends_on refers to itself`,
+		`config.yaml:11:5: NAS: depends_on cycle NAS -> Plex -> NAS`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("problems:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Watcher reloads a config file when its contents change on disk (by
+// polling) or when Trigger is called, e.g. on SIGHUP. Included files,
+// new files matching an include glob and secret files count as changes
+// too.
+//
+// A reload that fails to load is reported through onError and the
+// previous config stays in effect.
+type Watcher struct {
+	path     string
+	poll     time.Duration
+	onReload func(*Config)
+	onError  func(error)
+
+	trigger chan struct{}
+	files   []string // files and globs to hash, from the last loaded config
+	sum     []byte   // hash of the last loaded contents
+}
+
+// NewWatcher returns a Watcher for path polling every poll interval.
+// current is the config already in use (nil if none), so the files it
+// was assembled from are watched from the start. onReload receives
+// every successfully loaded config; onError (may be nil) receives load
+// failures.
+func NewWatcher(path string, current *Config, poll time.Duration, onReload func(*Config), onError func(error)) *Watcher {
+	w := &Watcher{
+		path:     path,
+		poll:     poll,
+		onReload: onReload,
+		onError:  onError,
+		trigger:  make(chan struct{}, 1),
+		files:    []string{path},
+	}
+	if current != nil && len(current.watch) > 0 {
+		w.files = current.watch
+	}
+	w.sum = w.hash()
+	return w
+}
+
+// Trigger requests a reload even if the file looks unchanged.
+// It never blocks.
+func (w *Watcher) Trigger() {
+	select {
+	case w.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Run polls for changes until ctx is cancelled.
+func (w *Watcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.poll)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.trigger:
+			w.reload()
+		case <-ticker.C:
+			if bytes.Equal(w.hash(), w.sum) {
+				continue
+			}
+			w.reload()
+		}
+	}
+}
+
+// reload loads the file and hands it to onReload.
+func (w *Watcher) reload() {
+	sum := w.hash()
+
+	cfg, err := Load(w.path)
+	if err != nil {
+		// Remember the broken contents so we do not retry (and log)
+		// every poll; the next edit or Trigger tries again.
+		w.sum = sum
+		if w.onError != nil {
+			w.onError(err)
+		}
+		return
+	}
+
+	// The new config may include different files; hash those.
+	w.files = cfg.watch
+	w.sum = w.hash()
+	w.onReload(cfg)
+}
+
+// hash returns a digest of the names and contents of every watched
+// file. Globs are expanded each time so new included files are seen;
+// missing files are skipped.
+func (w *Watcher) hash() []byte {
+	h := sha256.New()
+	for _, pattern := range w.files {
+		matches, _ := filepath.Glob(pattern)
+		for _, file := range matches {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(h, "%s\x00%d\x00", file, len(data))
+			h.Write(data)
+		}
+	}
+	return h.Sum(nil)
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatcherReloadsOnChangeAndTrigger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, path, "services:\n  - name: A\n    url: http://a\n")
+
+	reloads := make(chan *Config, 4)
+	errs := make(chan error, 4)
+	w := NewWatcher(path, nil, 10*time.Millisecond,
+		func(c *Config) { reloads <- c },
+		func(err error) { errs <- err },
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go w.Run(ctx)
+
+	// Unchanged file: no reload.
+	select {
+	case <-reloads:
+		t.Fatalf("unexpected reload of unchanged file")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	writeFile(t, path, "services:\n  - name: A\n    url: http://a\n  - name: B\n    url: http://b\n")
+	if cfg := recv(t, reloads); len(cfg.Services) != 2 {
+		t.Fatalf("reloaded %d services, want 2", len(cfg.Services))
+	}
+
+	w.Trigger()
+	if cfg := recv(t, reloads); len(cfg.Services) != 2 {
+		t.Fatalf("triggered reload returned %d services, want 2", len(cfg.Services))
+	}
+
+	// Broken YAML is reported once and not applied.
+	writeFile(t, path, "services: [\n")
+	if err := recv(t, errs); err == nil {
+		t.Fatalf("expected an error for broken YAML")
+	}
+	select {
+	case <-reloads:
+		t.Fatalf("broken config must not be applied")
+	case err := <-errs:
+		t.Fatalf("broken config reported twice: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func recv[T any](t *testing.T, ch <-chan T) T {
+	t.Helper()
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(2 * time.Second):
+		var zero T
+		t.Fatalf("timed out waiting for %T", zero)
+		return zero
+	}
+}
@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/health"
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/models"
+)
+
+// The JSON API exposes the same data as the dashboard under /api/v1:
+//
+//	GET  /api/v1/services                 all services, dashboard order
+//	GET  /api/v1/services/{name}          one service
+//	POST /api/v1/services/{name}/recheck  check now, return the result
+//	GET  /api/v1/summary                  counts and availability rollup
+//
+// Silences have their own endpoints, see silences.go.
+//
+// Errors are returned as {"error": "..."} with a matching status code.
+
+// APIService is the JSON form of a ServiceView.
+type APIService struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Category    string   `json:"category,omitempty"`
+	Description string   `json:"description,omitempty"`
+	URL         string   `json:"url,omitempty"`
+	DependsOn   []string `json:"depends_on,omitempty"`
+
+	Status      string      `json:"status"`
+	RawStatus   string      `json:"raw_status,omitempty"`
+	Stale       bool        `json:"stale"`
+	Restored    bool        `json:"restored,omitempty"`
+	LatencyMs   int64       `json:"latency_ms"`
+	Latency     *APILatency `json:"latency,omitempty"`
+	LastChecked *time.Time  `json:"last_checked,omitempty"`
+
+	Error       string `json:"error,omitempty"`
+	Warning     string `json:"warning,omitempty"`
+	ReasonClass string `json:"reason_class,omitempty"`
+	ReasonLabel string `json:"reason_label,omitempty"`
+	CertNote    string `json:"cert_note,omitempty"`
+
+	UpstreamIssue bool   `json:"upstream_issue"`
+	UpstreamNote  string `json:"upstream_note,omitempty"`
+	RootCause     string `json:"root_cause,omitempty"` // UNREACHABLE only
+
+	Maintenance *APIMaintenance `json:"maintenance,omitempty"`
+
+	// Uptime maps a window ("24h", "7d", "30d") to percent available.
+	Uptime map[string]float64 `json:"uptime,omitempty"`
+}
+
+// APILatency holds latency percentiles over recent passing checks.
+type APILatency struct {
+	Samples int   `json:"samples"`
+	P50Ms   int64 `json:"p50_ms"`
+	P95Ms   int64 `json:"p95_ms"`
+	P99Ms   int64 `json:"p99_ms"`
+}
+
+// APIMaintenance is the maintenance window or silence a service is in.
+type APIMaintenance struct {
+	Name   string    `json:"name"` // window name, or silence ID
+	By     string    `json:"by,omitempty"`
+	Reason string    `json:"reason,omitempty"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+}
+
+// APISummary is the JSON form of the summary banner.
+type APISummary struct {
+	Total    int `json:"total"`
+	Up       int `json:"up"`
+	Down     int `json:"down"`
+	Stale    int `json:"stale"`
+	Degraded int `json:"degraded"`
+	Unknown  int `json:"unknown"`
+
+	Maintenance int `json:"maintenance"`
+	Unreachable int `json:"unreachable"`
+
+	Severity string `json:"severity"` // "ok", "warning", "critical" or "unknown"
+	Title    string `json:"title"`
+	Message  string `json:"message"`
+
+	TopReason      string `json:"top_reason,omitempty"`
+	TopReasonCount int    `json:"top_reason_count,omitempty"`
+
+	Uptime30d  *float64            `json:"uptime_30d,omitempty"`
+	Categories []APICategoryUptime `json:"categories,omitempty"`
+
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+// APICategoryUptime is 30-day availability for one category.
+type APICategoryUptime struct {
+	Category        string  `json:"category"`
+	Uptime          float64 `json:"uptime_30d"`
+	DowntimeSeconds float64 `json:"downtime_seconds"`
+	Incidents       int     `json:"incidents"`
+	MTTRSeconds     float64 `json:"mttr_seconds,omitempty"`
+}
+
+// APIServices handles GET /api/v1/services.
+func (h *DashboardHandler) APIServices(w http.ResponseWriter, r *http.Request) {
+	data := h.buildViewData()
+	byName := servicesByName(h.currentServices())
+
+	out := make([]APIService, 0, len(data.Services))
+	for _, v := range data.Services {
+		out = append(out, h.apiService(v, byName[v.Name]))
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"services": out})
+}
+
+// APIService handles GET /api/v1/services/{name}.
+func (h *DashboardHandler) APIService(w http.ResponseWriter, r *http.Request) {
+	h.writeAPIService(w, r.PathValue("name"))
+}
+
+// APIRecheck handles POST /api/v1/services/{name}/recheck, mirroring
+// RecheckService: the check runs synchronously and the updated service
+// is returned.
+func (h *DashboardHandler) APIRecheck(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if ok := h.checker.CheckNow(r.Context(), name); !ok {
+		writeAPIError(w, http.StatusNotFound, "service not found")
+		return
+	}
+	h.writeAPIService(w, name)
+}
+
+// APISummary handles GET /api/v1/summary.
+func (h *DashboardHandler) APISummary(w http.ResponseWriter, r *http.Request) {
+	data := h.buildViewData()
+	counts := SummarizeServices(data.Services)
+	s := data.Summary
+
+	out := APISummary{
+		Total:          len(data.Services),
+		Up:             counts.UpCount,
+		Down:           counts.DownCount,
+		Stale:          counts.StaleCount,
+		Degraded:       counts.DegradedCount,
+		Unknown:        counts.UnknownCount,
+		Maintenance:    counts.MaintenanceCount,
+		Unreachable:    counts.UnreachableCount,
+		Severity:       apiSeverity(s.SeverityClass),
+		Title:          s.Title,
+		Message:        s.Message,
+		TopReason:      s.TopReasonLabel,
+		TopReasonCount: s.TopReasonCount,
+		GeneratedAt:    time.Now().UTC(),
+	}
+
+	if s.month.HasData() {
+		p := s.month.Percent()
+		out.Uptime30d = &p
+	}
+	for _, c := range s.Categories {
+		out.Categories = append(out.Categories, APICategoryUptime{
+			Category:        c.category,
+			Uptime:          c.month.Percent(),
+			DowntimeSeconds: c.month.Downtime.Seconds(),
+			Incidents:       c.month.Incidents,
+			MTTRSeconds:     c.month.MTTR().Seconds(),
+		})
+	}
+
+	writeJSON(w, http.StatusOK, out)
+}
+
+// writeAPIService writes the current view of one service, or 404.
+func (h *DashboardHandler) writeAPIService(w http.ResponseWriter, name string) {
+	v, svc, ok := h.findServiceView(name)
+	if !ok {
+		writeAPIError(w, http.StatusNotFound, "service not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, h.apiService(v, svc))
+}
+
+// apiService converts a tile into its JSON form.
+func (h *DashboardHandler) apiService(v ServiceView, svc models.Service) APIService {
+	out := APIService{
+		Name:          v.Name,
+		Type:          strings.ToLower(v.Protocol),
+		Category:      v.Category,
+		Description:   v.Description,
+		URL:           v.URL,
+		DependsOn:     svc.DependsOn,
+		Status:        v.Status,
+		RawStatus:     v.RawStatus,
+		Stale:         v.IsStale,
+		Restored:      v.Restored,
+		LatencyMs:     v.LatencyMs,
+		Error:         v.LastError,
+		Warning:       v.Warning,
+		ReasonClass:   v.ReasonClass,
+		ReasonLabel:   v.ReasonLabel,
+		CertNote:      v.CertNote,
+		UpstreamIssue: v.UpstreamIssue,
+		UpstreamNote:  v.UpstreamNote,
+		RootCause:     v.rootCause,
+	}
+
+	if !v.LastChecked.IsZero() {
+		t := v.LastChecked.UTC()
+		out.LastChecked = &t
+	}
+	if m := v.maintenance; m != nil {
+		out.Maintenance = &APIMaintenance{
+			Name:   m.Name,
+			By:     m.By,
+			Reason: m.Reason,
+			Start:  m.Start.UTC(),
+			End:    m.End.UTC(),
+		}
+	}
+	if st := h.checker.LatencyStats(v.Name); st.Samples > 0 {
+		out.Latency = &APILatency{
+			Samples: st.Samples,
+			P50Ms:   st.P50.Milliseconds(),
+			P95Ms:   st.P95.Milliseconds(),
+			P99Ms:   st.P99.Milliseconds(),
+		}
+	}
+	for _, win := range health.UptimeWindows {
+		a := h.checker.Availability(v.Name, win)
+		if !a.HasData() {
+			continue
+		}
+		if out.Uptime == nil {
+			out.Uptime = make(map[string]float64, len(health.UptimeWindows))
+		}
+		out.Uptime[windowLabel(win)] = a.Percent()
+	}
+	return out
+}
+
+func servicesByName(services []models.Service) map[string]models.Service {
+	m := make(map[string]models.Service, len(services))
+	for _, svc := range services {
+		m[svc.Name] = svc
+	}
+	return m
+}
+
+// apiSeverity maps the banner's Bulma class to a stable API value.
+func apiSeverity(class string) string {
+	switch class {
+	case "is-success", "is-info":
+		return "ok"
+	case "is-warning":
+		return "warning"
+	case "is-danger":
+		return "critical"
+	default:
+		return "unknown"
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("error writing API response: %v", err)
+	}
+}
+
+func writeAPIError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
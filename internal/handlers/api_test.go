@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/health"
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/models"
+)
+
+// newAPITestServer serves the JSON API for services checked against a
+// local HTTP server whose /down path fails.
+func newAPITestServer(t *testing.T) (*httptest.Server, *health.Checker) {
+	t.Helper()
+
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/down" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	t.Cleanup(backend.Close)
+
+	services := []models.Service{
+		{Name: "NAS", URL: backend.URL + "/down", Category: "Storage"},
+		{Name: "Plex", URL: backend.URL + "/ok", Category: "Media", DependsOn: []string{"NAS"}},
+		{Name: "Router", Type: "tcp", Host: "127.0.0.1", Port: 1},
+	}
+	c := health.NewChecker(services, time.Hour, time.Second, time.Second)
+	c.CheckNow(t.Context(), "NAS")
+	c.CheckNow(t.Context(), "Plex")
+
+	h := &DashboardHandler{checker: c, services: services}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/services", h.APIServices)
+	mux.HandleFunc("GET /api/v1/services/{name}", h.APIService)
+	mux.HandleFunc("POST /api/v1/services/{name}/recheck", h.APIRecheck)
+	mux.HandleFunc("GET /api/v1/summary", h.APISummary)
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv, c
+}
+
+func getJSON(t *testing.T, method, url string, wantStatus int, out any) {
+	t.Helper()
+
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != wantStatus {
+		t.Fatalf("%s %s: status %d, want %d", method, url, resp.StatusCode, wantStatus)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" && wantStatus != http.StatusMethodNotAllowed {
+		t.Fatalf("%s %s: content type %q", method, url, ct)
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("%s %s: decode: %v", method, url, err)
+		}
+	}
+}
+
+func TestAPIServices(t *testing.T) {
+	srv, _ := newAPITestServer(t)
+
+	var list struct {
+		Services []APIService `json:"services"`
+	}
+	getJSON(t, http.MethodGet, srv.URL+"/api/v1/services", http.StatusOK, &list)
+
+	if len(list.Services) != 3 {
+		t.Fatalf("got %d services, want 3", len(list.Services))
+	}
+	// Dashboard order: DOWN first, UNKNOWN, then UP.
+	nas, router, plex := list.Services[0], list.Services[1], list.Services[2]
+	if nas.Name != "NAS" || nas.Status != "DOWN" || nas.ReasonClass == "" || nas.Error == "" || nas.LastChecked == nil {
+		t.Errorf("NAS = %+v", nas)
+	}
+	if router.Name != "Router" || router.Status != "UNKNOWN" || router.Type != "tcp" || router.LastChecked != nil {
+		t.Errorf("Router = %+v", router)
+	}
+	if plex.Name != "Plex" || plex.Status != "UP" || plex.Type != "http" || plex.Latency == nil || plex.DependsOn[0] != "NAS" {
+		t.Errorf("Plex = %+v", plex)
+	}
+
+	var one APIService
+	getJSON(t, http.MethodGet, srv.URL+"/api/v1/services/Plex", http.StatusOK, &one)
+	if one.Name != "Plex" || one.Category != "Media" {
+		t.Errorf("single service = %+v", one)
+	}
+
+	var apiErr map[string]string
+	getJSON(t, http.MethodGet, srv.URL+"/api/v1/services/Nope", http.StatusNotFound, &apiErr)
+	if apiErr["error"] != "service not found" {
+		t.Errorf("error body = %v", apiErr)
+	}
+}
+
+func TestAPIRecheck(t *testing.T) {
+	srv, c := newAPITestServer(t)
+
+	var got APIService
+	getJSON(t, http.MethodPost, srv.URL+"/api/v1/services/Router/recheck", http.StatusOK, &got)
+	if got.Status != "DOWN" || got.ReasonClass != "CONN" {
+		t.Fatalf("recheck result = %+v", got)
+	}
+	if _, ok := c.Snapshot()["Router"]; !ok {
+		t.Fatalf("recheck did not store a result")
+	}
+
+	getJSON(t, http.MethodPost, srv.URL+"/api/v1/services/Nope/recheck", http.StatusNotFound, nil)
+	getJSON(t, http.MethodGet, srv.URL+"/api/v1/services/Router/recheck", http.StatusMethodNotAllowed, nil)
+}
+
+func TestAPISummary(t *testing.T) {
+	srv, _ := newAPITestServer(t)
+
+	var s APISummary
+	getJSON(t, http.MethodGet, srv.URL+"/api/v1/summary", http.StatusOK, &s)
+
+	if s.Total != 3 || s.Up != 1 || s.Down != 1 || s.Unknown != 1 {
+		t.Fatalf("counts = %+v", s)
+	}
+	if s.Severity != "critical" || s.TopReason == "" {
+		t.Fatalf("severity=%q top reason=%q", s.Severity, s.TopReason)
+	}
+	if s.Uptime30d == nil || len(s.Categories) != 2 {
+		t.Fatalf("uptime=%v categories=%+v", s.Uptime30d, s.Categories)
+	}
+	// Same rollup as the banner: sorted by category, Media all UP.
+	media, storage := s.Categories[0], s.Categories[1]
+	if media.Category != "Media" || media.Uptime != 100 || storage.Category != "Storage" || storage.Uptime >= 100 {
+		t.Fatalf("categories = %+v", s.Categories)
+	}
+}
+
+func TestAPIRootCause(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(backend.Close)
+
+	services := []models.Service{
+		{Name: "NAS", URL: backend.URL},
+		{Name: "Plex", URL: backend.URL, DependsOn: []string{"NAS"}},
+	}
+	c := health.NewChecker(services, time.Hour, time.Second, time.Second)
+	c.CheckNow(t.Context(), "NAS")
+	c.CheckNow(t.Context(), "Plex")
+
+	h := &DashboardHandler{checker: c, services: services}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/services/{name}", h.APIService)
+	mux.HandleFunc("GET /api/v1/summary", h.APISummary)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	var plex APIService
+	getJSON(t, http.MethodGet, srv.URL+"/api/v1/services/Plex", http.StatusOK, &plex)
+	if plex.Status != "UNREACHABLE" || plex.RootCause != "NAS" || plex.UpstreamNote != "Upstream: NAS is DOWN" {
+		t.Fatalf("Plex = %+v", plex)
+	}
+
+	var s APISummary
+	getJSON(t, http.MethodGet, srv.URL+"/api/v1/summary", http.StatusOK, &s)
+	if s.Down != 1 || s.Unreachable != 1 || s.TopReasonCount != 1 {
+		t.Fatalf("summary = %+v", s)
+	}
+}
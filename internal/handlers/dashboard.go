@@ -8,10 +8,13 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/cyber-mountain-man/aurora-homelab-go/internal/health"
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/maintenance"
 	"github.com/cyber-mountain-man/aurora-homelab-go/internal/models"
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/redact"
 )
 
 // ServiceView is what the template sees: service + health fields.
@@ -25,67 +28,159 @@ type ServiceView struct {
 	StatusClass string
 	LatencyMs   int64
 
+	// latency percentiles over recent passing checks
+	LatencyNote string // e.g. "p50 12 ms • p95 40 ms • p99 85 ms"
+
+	// flap suppression: raw result of the last check, and a note while
+	// it disagrees with the displayed (debounced) Status
+	RawStatus    string
+	DebounceNote string
+
 	Protocol      string
 	ProtocolClass string
 
 	LastError   string
 	LastChecked time.Time
 
+	// Warning is a non-fatal problem on an otherwise healthy service.
+	Warning string
+
+	// CertNote summarizes the certificate for TLS checks.
+	CertNote string
+
 	IsStale    bool
 	StaleClass string
 	StaleLabel string
 
 	JustChecked bool
 
-	// dependency correlation
+	// Restored is true while the tile shows state loaded from disk on
+	// startup rather than a fresh check.
+	Restored bool
+
+	// dependency correlation: the root cause of an UNREACHABLE service
 	UpstreamIssue bool
 	UpstreamNote  string
+	rootCause     string
+
+	// maintenance: who or what put the service in maintenance, and the
+	// silence that can be lifted from the tile (empty for windows)
+	MaintenanceNote string
+	SilenceID       string
+	CanSilence      bool
+	maintenance     *health.Maintenance
 
 	// semantic reason classification
 	ReasonClass string // e.g. "TIMEOUT", "DNS", "CONN", "PERMISSION"
 	ReasonLabel string // short human label
 	ReasonColor string // Bulma tag class, e.g. "is-warning"
+
+	// availability over health.UptimeWindows, plus a 30d detail line
+	Uptime     []UptimeView
+	UptimeNote string
+
+	// month is the 30d availability used for summary rollups.
+	month health.Availability
+}
+
+// UptimeView is one availability figure, e.g. {"24h", "99.95%"}.
+type UptimeView struct {
+	Label   string
+	Percent string
+	Class   string // Bulma text color class
 }
 
 // DashboardHandler holds compiled templates, services, and the health checker.
 type DashboardHandler struct {
-	tmpl     *template.Template
+	tmpl    *template.Template
+	checker *health.Checker
+
+	mu       sync.RWMutex
 	services []models.Service
-	checker  *health.Checker
+
+	// silences, when set, lets users silence services from the tiles
+	// and the API.
+	silences *maintenance.Manager
+
+	// closing is closed by CloseStreams to end open event streams.
+	closing   chan struct{}
+	closeOnce sync.Once
+}
+
+// DashboardOption customizes a DashboardHandler.
+type DashboardOption func(*DashboardHandler)
+
+// WithSilences enables silencing services from the dashboard and the
+// /api/v1/silences endpoints.
+func WithSilences(m *maintenance.Manager) DashboardOption {
+	return func(h *DashboardHandler) {
+		h.silences = m
+	}
 }
 
 // NewDashboardHandler parses the HTML templates and returns a handler.
-func NewDashboardHandler(templatesDir string, services []models.Service, checker *health.Checker) (*DashboardHandler, error) {
+func NewDashboardHandler(templatesDir string, services []models.Service, checker *health.Checker, opts ...DashboardOption) (*DashboardHandler, error) {
 	layout := filepath.Join(templatesDir, "layout.html")
 	dashboard := filepath.Join(templatesDir, "dashboard.html")
 	serviceTile := filepath.Join(templatesDir, "service_tile.html")
 	summaryBanner := filepath.Join(templatesDir, "summary_banner.html")
+	serviceHistory := filepath.Join(templatesDir, "service_history.html")
+	silenceForm := filepath.Join(templatesDir, "silence_form.html")
 
 	tmpl, err := template.New("layout.html").
 		Funcs(template.FuncMap{
 			"safeid": safeID,
 		}).
-		ParseFiles(layout, dashboard, serviceTile, summaryBanner)
+		ParseFiles(layout, dashboard, serviceTile, summaryBanner, serviceHistory, silenceForm)
 	if err != nil {
 		return nil, err
 	}
 
-	return &DashboardHandler{
+	h := &DashboardHandler{
 		tmpl:     tmpl,
 		services: services,
 		checker:  checker,
-	}, nil
+		closing:  make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h, nil
 }
 
 type HealthSummary struct {
-	SeverityClass  string // Bulma class: is-success / is-warning / is-danger / is-dark
-	Title          string // Short headline
-	Message        string // 1–2 lines of detail
-	DownCount      int
-	StaleCount     int
-	UnknownCount   int
-	TopReasonLabel string // e.g., "DNS", "Timeout"
-	TopReasonCount int
+	SeverityClass    string // Bulma class: is-success / is-info / is-warning / is-danger / is-dark
+	Title            string // Short headline
+	Message          string // 1–2 lines of detail
+	DownCount        int
+	StaleCount       int
+	UnknownCount     int
+	DegradedCount    int
+	MaintenanceCount int
+	UnreachableCount int
+	TopReasonLabel   string // e.g., "DNS", "Timeout"
+	TopReasonCount   int
+
+	// 30-day availability across all services and per category.
+	UptimeLine string
+	Categories []CategoryUptime
+
+	// month is the combined 30d availability behind UptimeLine.
+	month health.Availability
+}
+
+// CategoryUptime is a per-category availability rollup for the banner.
+type CategoryUptime struct {
+	Category string
+	Percent  string
+	Downtime string
+	MTTR     string
+	Class    string
+
+	// category is the configured name ("" for Uncategorized) and month
+	// the combined 30d availability of its services.
+	category string
+	month    health.Availability
 }
 
 // viewData is what we pass into the templates.
@@ -95,133 +190,29 @@ type viewData struct {
 	Summary  HealthSummary
 }
 
+// SetServices replaces the services shown on the dashboard, e.g. after
+// a config reload.
+func (h *DashboardHandler) SetServices(services []models.Service) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.services = services
+}
+
+// currentServices returns the services currently shown.
+func (h *DashboardHandler) currentServices() []models.Service {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.services
+}
+
 // buildViewData creates the view model from services + health results.
 func (h *DashboardHandler) buildViewData() viewData {
 	results := h.checker.Snapshot()
-	staleAfter := 2*h.checker.Interval() + 10*time.Second
-
-	views := make([]ServiceView, 0, len(h.services))
-	indexByName := make(map[string]int, len(h.services))
-
-	// Pass 1: build tiles from results
-	for _, svc := range h.services {
-		protoLabel := protocolLabel(svc.Type)
-
-		v := ServiceView{
-			Name:        svc.Name,
-			Description: svc.Description,
-			Category:    svc.Category,
-			URL:         svc.URL,
-
-			Status:      string(health.StatusUnknown),
-			StatusClass: "is-dark",
-			LatencyMs:   0,
-
-			Protocol:      protoLabel,
-			ProtocolClass: protocolClass(protoLabel),
-
-			LastError:   "",
-			LastChecked: time.Time{},
-
-			IsStale:    false,
-			StaleClass: "",
-			StaleLabel: "",
-
-			UpstreamIssue: false,
-			UpstreamNote:  "",
-		}
+	services := h.currentServices()
 
-		if res, ok := results[svc.Name]; ok {
-			v.Status = strings.TrimSpace(string(res.Status))
-			v.StatusClass = bulmaClassForStatus(res.Status)
-			v.LatencyMs = res.Latency.Milliseconds()
-			v.LastChecked = res.CheckedAt
-			v.LastError = res.Error
-
-			// Semantic reason classification for errors
-			if v.LastError != "" {
-				rc := health.ClassifyError(v.LastError)
-				label, color := health.ReasonPresentation(rc)
-				v.ReasonClass = string(rc)
-				v.ReasonLabel = label
-				v.ReasonColor = color
-			}
-
-			// Stale detection
-			if !res.CheckedAt.IsZero() && time.Since(res.CheckedAt) > staleAfter {
-				v.IsStale = true
-				v.StaleClass = "is-warning"
-				v.StaleLabel = "STALE"
-
-				// If stale but no error, attach a default "reason"
-				if v.LastError == "" {
-					v.LastError = "stale: no recent health result"
-
-					rc := health.ReasonTimeout // or create ReasonStale later
-					label, color := health.ReasonPresentation(rc)
-					v.ReasonClass = string(rc)
-					v.ReasonLabel = label
-					v.ReasonColor = color
-				}
-			}
-		}
-
-		views = append(views, v)
-		indexByName[v.Name] = len(views) - 1
-	}
-
-	// Pass 2: dependency correlation (depends_on)
-	for i, svc := range h.services {
-		if len(svc.DependsOn) == 0 {
-			continue
-		}
-
-		worstDepName := ""
-		worstDepStatus := ""
-		worstRank := 999
-
-		for _, depName := range svc.DependsOn {
-			depIdx, ok := indexByName[depName]
-			if !ok {
-				// dependency name not found in config
-				if worstRank > 3 {
-					worstRank = 3
-					worstDepName = depName
-					worstDepStatus = "MISSING"
-				}
-				continue
-			}
-
-			dep := views[depIdx]
-
-			// rank: DOWN(0), STALE(1), UNKNOWN(2), UP(3)
-			r := 3
-			if dep.Status == string(health.StatusDown) {
-				r = 0
-			} else if dep.IsStale {
-				r = 1
-			} else if dep.Status == string(health.StatusUnknown) {
-				r = 2
-			}
-
-			if r < worstRank {
-				worstRank = r
-				worstDepName = dep.Name
-				if dep.IsStale {
-					worstDepStatus = "STALE"
-				} else {
-					worstDepStatus = dep.Status
-				}
-			}
-		}
-
-		// Only show upstream hint when it helps (service not clearly UP)
-		if worstDepName != "" && worstRank <= 2 {
-			if views[i].Status != string(health.StatusUp) || views[i].IsStale {
-				views[i].UpstreamIssue = true
-				views[i].UpstreamNote = "Upstream: " + worstDepName + " is " + worstDepStatus
-			}
-		}
+	views := make([]ServiceView, 0, len(services))
+	for _, svc := range services {
+		views = append(views, h.serviceView(svc, results))
 	}
 
 	sort.SliceStable(views, func(i, j int) bool {
@@ -253,6 +244,140 @@ func (h *DashboardHandler) buildViewData() viewData {
 	}
 }
 
+// serviceView builds the tile of svc from the latest results.
+func (h *DashboardHandler) serviceView(svc models.Service, results map[string]health.Result) ServiceView {
+	protoLabel := protocolLabel(svc.Type)
+
+	v := ServiceView{
+		Name:        svc.Name,
+		Description: svc.Description,
+		Category:    svc.Category,
+		URL:         h.checker.Redact(redact.URL(svc.URL)),
+
+		Status:      string(health.StatusUnknown),
+		StatusClass: "is-dark",
+		LatencyMs:   0,
+
+		Protocol:      protoLabel,
+		ProtocolClass: protocolClass(protoLabel),
+
+		LastError:   "",
+		LastChecked: time.Time{},
+
+		IsStale:    false,
+		StaleClass: "",
+		StaleLabel: "",
+
+		UpstreamIssue: false,
+		UpstreamNote:  "",
+
+		CanSilence: h.silences != nil,
+	}
+
+	if res, ok := results[svc.Name]; ok {
+		v.Status = strings.TrimSpace(string(res.Status))
+		v.RawStatus = string(res.RawStatus)
+		if res.Maintenance != nil {
+			v.maintenance = res.Maintenance
+			v.MaintenanceNote = maintenanceNote(*res.Maintenance)
+			if res.Maintenance.By != "" {
+				v.SilenceID = res.Maintenance.Name
+			}
+		} else if res.RootCause != "" {
+			v.UpstreamIssue = true
+			v.rootCause = res.RootCause
+			v.UpstreamNote = upstreamNote(res.RootCause, results[res.RootCause].Status)
+		} else if res.RawStatus != "" && res.RawStatus != res.Status {
+			v.DebounceNote = debounceNote(svc, res)
+		}
+		v.StatusClass = bulmaClassForStatus(res.Status)
+		v.LatencyMs = res.Latency.Milliseconds()
+		if st := h.checker.LatencyStats(svc.Name); st.Samples > 1 {
+			v.LatencyNote = "p50 " + itoa(int(st.P50.Milliseconds())) + " ms • p95 " +
+				itoa(int(st.P95.Milliseconds())) + " ms • p99 " + itoa(int(st.P99.Milliseconds())) + " ms"
+		}
+		v.LastChecked = res.CheckedAt
+		v.LastError = h.checker.Redact(res.Error)
+		v.Warning = h.checker.Redact(res.Warning)
+		v.Restored = res.Restored
+
+		if !res.CertNotAfter.IsZero() {
+			v.CertNote = certNote(res)
+		}
+
+		// Semantic reason classification for errors
+		if v.LastError != "" {
+			rc := health.ClassifyError(v.LastError)
+			label, color := health.ReasonPresentation(rc)
+			v.ReasonClass = string(rc)
+			v.ReasonLabel = label
+			v.ReasonColor = color
+		}
+
+		// Stale detection (per-service interval)
+		staleAfter := 2*h.checker.IntervalFor(svc) + 10*time.Second
+		if !res.CheckedAt.IsZero() && time.Since(res.CheckedAt) > staleAfter {
+			v.IsStale = true
+			v.StaleClass = "is-warning"
+			v.StaleLabel = "STALE"
+
+			// If stale but no error, attach a default "reason"
+			if v.LastError == "" {
+				v.LastError = "stale: no recent health result"
+
+				rc := health.ReasonTimeout // or create ReasonStale later
+				label, color := health.ReasonPresentation(rc)
+				v.ReasonClass = string(rc)
+				v.ReasonLabel = label
+				v.ReasonColor = color
+			}
+		}
+	}
+
+	h.applyUptime(&v)
+	return v
+}
+
+// findServiceView builds the tile of the named service alone, for
+// handlers that render or return a single service.
+func (h *DashboardHandler) findServiceView(name string) (ServiceView, models.Service, bool) {
+	for _, svc := range h.currentServices() {
+		if svc.Name == name {
+			return h.serviceView(svc, h.checker.Snapshot()), svc, true
+		}
+	}
+	return ServiceView{}, models.Service{}, false
+}
+
+// applyUptime fills the availability fields of a tile.
+func (h *DashboardHandler) applyUptime(v *ServiceView) {
+	for _, w := range health.UptimeWindows {
+		a := h.checker.Availability(v.Name, w)
+		if w == health.Window30d {
+			v.month = a
+		}
+		if !a.HasData() {
+			continue
+		}
+		v.Uptime = append(v.Uptime, UptimeView{
+			Label:   windowLabel(w),
+			Percent: formatPercent(a.Percent()),
+			Class:   uptimeClass(a.Percent()),
+		})
+	}
+
+	if v.month.HasData() && v.month.Downtime > 0 {
+		v.UptimeNote = "30d downtime " + formatDuration(v.month.Downtime) +
+			" • " + itoa(v.month.Incidents) + " incidents"
+		if mttr := v.month.MTTR(); mttr > 0 {
+			v.UptimeNote += " • MTTR " + formatDuration(mttr)
+		}
+		if mtbf := v.month.MTBF(); mtbf > 0 {
+			v.UptimeNote += " • MTBF " + formatDuration(mtbf)
+		}
+	}
+}
+
 // Dashboard renders the main dashboard page with the full layout.
 func (h *DashboardHandler) Dashboard(w http.ResponseWriter, r *http.Request) {
 	data := h.buildViewData()
@@ -264,7 +389,8 @@ func (h *DashboardHandler) Dashboard(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// DashboardPartial renders ONLY the tiles (no layout) for HTMX polling.
+// DashboardPartial renders ONLY the tiles (no layout), for the initial
+// HTMX load and whenever the event stream asks for a refresh.
 func (h *DashboardHandler) DashboardPartial(w http.ResponseWriter, r *http.Request) {
 	data := h.buildViewData()
 
@@ -285,8 +411,12 @@ func bulmaClassForStatus(s health.Status) string {
 		return "is-success"
 	case health.StatusDown:
 		return "is-danger"
-	case health.StatusStale:
+	case health.StatusUnreachable:
+		return "is-danger is-light"
+	case health.StatusStale, health.StatusDegraded:
 		return "is-warning"
+	case health.StatusMaintenance:
+		return "is-info"
 	default:
 		return "is-dark"
 	}
@@ -303,6 +433,8 @@ func protocolLabel(svcType string) string {
 		return "DNS"
 	case "ping":
 		return "PING"
+	case "tls":
+		return "TLS"
 	default:
 		if svcType == "" {
 			return ""
@@ -322,6 +454,8 @@ func protocolClass(p string) string {
 		return "is-primary"
 	case "PING":
 		return "is-success"
+	case "TLS":
+		return "is-link"
 	default:
 		return "is-dark"
 	}
@@ -332,14 +466,23 @@ func severityRank(v ServiceView) int {
 	if v.Status == string(health.StatusDown) {
 		return 0
 	}
-	if v.IsStale {
+	if v.Status == string(health.StatusUnreachable) {
 		return 1
 	}
-	if v.Status == string(health.StatusUnknown) {
+	if v.IsStale {
 		return 2
 	}
+	if v.Status == string(health.StatusDegraded) {
+		return 3
+	}
+	if v.Status == string(health.StatusUnknown) {
+		return 4
+	}
+	if v.Status == string(health.StatusMaintenance) {
+		return 5
+	}
 	// UP (or anything else) last
-	return 3
+	return 6
 }
 
 func (h *DashboardHandler) RecheckService(w http.ResponseWriter, r *http.Request) {
@@ -354,21 +497,13 @@ func (h *DashboardHandler) RecheckService(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	if ok := h.checker.CheckNow(name); !ok {
+	if ok := h.checker.CheckNow(r.Context(), name); !ok {
 		http.Error(w, "service not found", http.StatusNotFound)
 		return
 	}
 
-	data := h.buildViewData()
-
-	var tile *ServiceView
-	for i := range data.Services {
-		if data.Services[i].Name == name {
-			tile = &data.Services[i]
-			break
-		}
-	}
-	if tile == nil {
+	tile, _, ok := h.findServiceView(name)
+	if !ok {
 		http.Error(w, "service not found", http.StatusNotFound)
 		return
 	}
@@ -377,7 +512,7 @@ func (h *DashboardHandler) RecheckService(w http.ResponseWriter, r *http.Request
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 
-	if err := h.tmpl.ExecuteTemplate(w, "service_tile", tile); err != nil {
+	if err := h.tmpl.ExecuteTemplate(w, "service_tile_body", tile); err != nil {
 		log.Printf("error rendering service tile: %v", err)
 		http.Error(w, "internal server error", http.StatusInternalServerError)
 		return
@@ -418,6 +553,18 @@ func buildSummary(views []ServiceView) HealthSummary {
 		if v.IsStale {
 			s.StaleCount++
 		}
+		if v.Status == string(health.StatusDegraded) {
+			s.DegradedCount++
+		}
+		if v.Status == string(health.StatusMaintenance) {
+			s.MaintenanceCount++
+			continue
+		}
+		// Unreachable services share their root cause's reason.
+		if v.Status == string(health.StatusUnreachable) {
+			s.UnreachableCount++
+			continue
+		}
 
 		// Only count a "reason" if we actually have one (usually DOWN/STALE)
 		if v.ReasonLabel != "" {
@@ -434,18 +581,29 @@ func buildSummary(views []ServiceView) HealthSummary {
 		}
 	}
 
+	summarizeUptime(&s, views)
+
 	// Decide banner severity + message (simple but effective rules)
 	if s.DownCount > 0 {
 		s.SeverityClass = "is-danger"
 		s.Title = "Service outages detected"
+		s.Message = "Down: " + itoa(s.DownCount)
+		if s.UnreachableCount > 0 {
+			s.Message += " • Unreachable: " + itoa(s.UnreachableCount)
+		}
 		if s.TopReasonCount > 0 {
-			s.Message = "Down: " + itoa(s.DownCount) + " • Top issue: " + s.TopReasonLabel + " (" + itoa(s.TopReasonCount) + ")"
-		} else {
-			s.Message = "Down: " + itoa(s.DownCount)
+			s.Message += " • Top issue: " + s.TopReasonLabel + " (" + itoa(s.TopReasonCount) + ")"
 		}
 		return s
 	}
 
+	if s.UnreachableCount > 0 {
+		s.SeverityClass = "is-warning"
+		s.Title = "Services unreachable"
+		s.Message = "Unreachable: " + itoa(s.UnreachableCount) + " • Something they depend on is failing"
+		return s
+	}
+
 	if s.StaleCount > 0 {
 		s.SeverityClass = "is-warning"
 		s.Title = "Stale checks detected"
@@ -453,6 +611,13 @@ func buildSummary(views []ServiceView) HealthSummary {
 		return s
 	}
 
+	if s.DegradedCount > 0 {
+		s.SeverityClass = "is-warning"
+		s.Title = "Degraded services detected"
+		s.Message = "Degraded: " + itoa(s.DegradedCount) + " • Slow responses or warnings"
+		return s
+	}
+
 	if s.UnknownCount > 0 {
 		s.SeverityClass = "is-dark"
 		s.Title = "Some services are unknown"
@@ -460,11 +625,155 @@ func buildSummary(views []ServiceView) HealthSummary {
 		return s
 	}
 
+	if s.MaintenanceCount > 0 {
+		s.SeverityClass = "is-info"
+		s.Title = "Maintenance in progress"
+		s.Message = "In maintenance: " + itoa(s.MaintenanceCount) + " • Everything else is UP"
+		return s
+	}
+
 	s.SeverityClass = "is-success"
 	s.Title = "All systems nominal"
 	s.Message = "Everything is UP"
 	return s
 }
 
+// maintenanceNote describes a maintenance window or silence, e.g.
+// "Silenced by alice until 14:05: kernel update".
+func maintenanceNote(m health.Maintenance) string {
+	until := m.End.Local().Format("15:04")
+	if m.End.Sub(time.Now()) > 24*time.Hour {
+		until = m.End.Local().Format("Jan 2 15:04")
+	}
+	note := "Maintenance " + strconv.Quote(m.Name) + " until " + until
+	if m.By != "" {
+		note = "Silenced by " + m.By + " until " + until
+	}
+	if m.Reason != "" {
+		note += ": " + m.Reason
+	}
+	return note
+}
+
+// upstreamNote names the root cause of an UNREACHABLE service, e.g.
+// "Upstream: Proxmox is DOWN".
+func upstreamNote(root string, status health.Status) string {
+	if status == health.StatusMaintenance {
+		return "Upstream: " + root + " is in maintenance"
+	}
+	return "Upstream: " + root + " is " + string(status)
+}
+
+// debounceNote explains why the displayed status differs from the last
+// raw check result, e.g. "Raw DOWN • 1/3 failures before DOWN".
+func debounceNote(svc models.Service, res health.Result) string {
+	note := "Raw " + string(res.RawStatus)
+	if res.RawStatus == health.StatusUp {
+		return note + " • " + itoa(res.ConsecutiveSuccesses) + "/" + itoa(svc.SuccessThreshold) + " successes before UP"
+	}
+	return note + " • " + itoa(res.ConsecutiveFailures) + "/" + itoa(svc.FailureThreshold) + " failures before DOWN"
+}
+
+// certNote renders certificate expiry and issuer for a TLS result.
+func certNote(res health.Result) string {
+	days := health.CertDaysLeft(res.CertNotAfter, time.Now())
+	note := "Cert expires " + res.CertNotAfter.Format("2006-01-02") + " (" + itoa(days) + "d)"
+	if res.CertIssuer != "" {
+		note += " • " + res.CertIssuer
+	}
+	return note
+}
+
+// summarizeUptime rolls 30d availability up overall and per category.
+func summarizeUptime(s *HealthSummary, views []ServiceView) {
+	var all []health.Availability
+	byCategory := make(map[string][]health.Availability)
+
+	for _, v := range views {
+		if !v.month.HasData() {
+			continue
+		}
+		all = append(all, v.month)
+		byCategory[v.Category] = append(byCategory[v.Category], v.month)
+	}
+
+	total := health.CombineAvailability(all...)
+	if !total.HasData() {
+		return
+	}
+	s.month = total
+	s.UptimeLine = "30d availability: " + formatPercent(total.Percent())
+
+	cats := make([]string, 0, len(byCategory))
+	for c := range byCategory {
+		cats = append(cats, c)
+	}
+	sort.Strings(cats)
+
+	for _, c := range cats {
+		a := health.CombineAvailability(byCategory[c]...)
+		name := c
+		if name == "" {
+			name = "Uncategorized"
+		}
+		cu := CategoryUptime{
+			Category: name,
+			Percent:  formatPercent(a.Percent()),
+			Class:    uptimeClass(a.Percent()),
+			category: c,
+			month:    a,
+		}
+		if a.Downtime > 0 {
+			cu.Downtime = formatDuration(a.Downtime)
+		}
+		if mttr := a.MTTR(); mttr > 0 {
+			cu.MTTR = formatDuration(mttr)
+		}
+		s.Categories = append(s.Categories, cu)
+	}
+}
+
+// windowLabel renders an uptime window as "24h", "7d" or "30d".
+func windowLabel(d time.Duration) string {
+	if d >= 48*time.Hour && d%(24*time.Hour) == 0 {
+		return itoa(int(d/(24*time.Hour))) + "d"
+	}
+	return itoa(int(d/time.Hour)) + "h"
+}
+
+// formatPercent renders an uptime percentage with two decimals.
+func formatPercent(p float64) string {
+	return strconv.FormatFloat(p, 'f', 2, 64) + "%"
+}
+
+// uptimeClass colors an uptime percentage.
+func uptimeClass(p float64) string {
+	switch {
+	case p >= 99.9:
+		return "has-text-success"
+	case p >= 99:
+		return "has-text-warning"
+	default:
+		return "has-text-danger"
+	}
+}
+
+// formatDuration renders a duration compactly, e.g. "3d4h", "2h15m", "45s".
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	switch {
+	case d >= 24*time.Hour:
+		days := d / (24 * time.Hour)
+		hours := (d % (24 * time.Hour)) / time.Hour
+		return itoa(int(days)) + "d" + itoa(int(hours)) + "h"
+	case d >= time.Hour:
+		return itoa(int(d/time.Hour)) + "h" + itoa(int((d%time.Hour)/time.Minute)) + "m"
+	case d >= time.Minute:
+		return itoa(int(d/time.Minute)) + "m"
+	default:
+		return itoa(int(d/time.Second)) + "s"
+	}
+}
+
 // tiny helper to avoid fmt.Sprintf noise
 func itoa(n int) string { return strconv.Itoa(n) }
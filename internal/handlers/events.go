@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// resyncEvery is how often an open event stream re-renders the
+// dashboard without an update from the checker, so tiles turn STALE
+// when checks stop arriving. Nothing is sent unless something changed.
+const resyncEvery = 15 * time.Second
+
+// Events handles GET /dashboard/events, a Server-Sent Events stream for
+// HTMX's SSE extension. Whenever the checker stores a result the
+// dashboard is re-rendered and only what changed is sent:
+//
+//	event: tile-<safeid>  a service_tile_body, swapped into the matching
+//	                      tile; its history panel is left alone
+//	event: summary        the summary_banner
+//	event: refresh        services were added, removed or re-sorted;
+//	                      the client reloads /dashboard/partial
+//
+// A reconnecting client (one that sends Last-Event-ID) may have missed
+// updates and is sent a refresh straight away.
+func (h *DashboardHandler) Events(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	updates := h.checker.SubscribeUpdates()
+	defer h.checker.UnsubscribeUpdates(updates)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // don't let nginx buffer the stream
+
+	s := &eventStream{w: w, h: h}
+	s.render(h.buildViewData())
+	if r.Header.Get("Last-Event-ID") != "" {
+		s.send("refresh", "")
+	} else {
+		// An id with no data sets the browser's Last-Event-ID without
+		// dispatching an event, so a reconnect is recognizable.
+		s.nextID++
+		s.write(fmt.Sprintf("id: %d\n\n", s.nextID))
+	}
+	if s.err != nil {
+		return
+	}
+	flusher.Flush()
+
+	resync := time.NewTicker(resyncEvery)
+	defer resync.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-h.closing:
+			return
+		case _, ok := <-updates:
+			if !ok {
+				return
+			}
+			drain(updates)
+		case <-resync.C:
+		}
+
+		if !s.push(h.buildViewData()) {
+			// Keep idle connections from being closed by proxies.
+			s.write(": keepalive\n\n")
+		}
+		if s.err != nil {
+			log.Printf("debug: event stream closed: %v", s.err)
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+// CloseStreams ends every open event stream, e.g. from
+// http.Server.RegisterOnShutdown so that Shutdown does not wait for
+// connections that never go idle.
+func (h *DashboardHandler) CloseStreams() {
+	h.closeOnce.Do(func() {
+		if h.closing != nil {
+			close(h.closing)
+		}
+	})
+}
+
+// drain discards queued updates: one re-render covers them all.
+func drain[T any](ch <-chan T) {
+	for {
+		select {
+		case _, ok := <-ch:
+			if !ok {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+// eventStream remembers what one client was last sent.
+type eventStream struct {
+	w      http.ResponseWriter
+	h      *DashboardHandler
+	nextID int
+	err    error
+
+	order   []string          // tile names in dashboard order
+	tiles   map[string]string // rendered tile by service name
+	summary string
+}
+
+// render renders data and replaces what the client is assumed to show.
+func (s *eventStream) render(data viewData) {
+	s.order = s.order[:0]
+	s.tiles = make(map[string]string, len(data.Services))
+	for _, v := range data.Services {
+		s.order = append(s.order, v.Name)
+		s.tiles[v.Name] = s.execute("service_tile_body", v)
+	}
+	s.summary = s.execute("summary_banner", data)
+}
+
+// push sends whatever differs between data and what the client shows,
+// and reports whether anything was sent.
+func (s *eventStream) push(data viewData) bool {
+	prevOrder, prevTiles, prevSummary := slices.Clone(s.order), s.tiles, s.summary
+	s.render(data)
+
+	// Tiles are swapped in place, so a change in the set or order of
+	// services needs the whole grid.
+	if !slices.Equal(prevOrder, s.order) {
+		s.send("refresh", "")
+		return true
+	}
+
+	sent := false
+	for _, name := range s.order {
+		if tile := s.tiles[name]; tile != prevTiles[name] {
+			s.send("tile-"+safeID(name), tile)
+			sent = true
+		}
+	}
+	if s.summary != prevSummary {
+		s.send("summary", s.summary)
+		sent = true
+	}
+	return sent
+}
+
+// execute renders a template to a string; errors are logged and
+// rendered as an empty fragment.
+func (s *eventStream) execute(name string, data any) string {
+	var buf bytes.Buffer
+	if err := s.h.tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		log.Printf("error rendering %s for event stream: %v", name, err)
+		return ""
+	}
+	return strings.TrimSpace(buf.String())
+}
+
+// send writes one event. Multi-line data is split across data fields
+// as the SSE format requires.
+func (s *eventStream) send(event, data string) {
+	s.nextID++
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "id: %d\nevent: %s\n", s.nextID, event)
+	for _, line := range strings.Split(data, "\n") {
+		b.WriteString("data: ")
+		b.WriteString(strings.TrimRight(line, "\r"))
+		b.WriteByte('\n')
+	}
+	b.WriteByte('\n')
+
+	s.write(b.String())
+}
+
+// write writes raw stream data, remembering the first error.
+func (s *eventStream) write(data string) {
+	if s.err == nil {
+		_, s.err = io.WriteString(s.w, data)
+	}
+}
@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/health"
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/models"
+)
+
+// sseEvent is one parsed Server-Sent Event.
+type sseEvent struct {
+	name, data string
+}
+
+// openEvents connects to the event stream and returns a channel of its
+// events. Comments and id-only frames are skipped.
+func openEvents(t *testing.T, url, lastEventID string) <-chan sseEvent {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("content type %q", ct)
+	}
+
+	events := make(chan sseEvent, 16)
+	go func() {
+		defer close(events)
+		sc := bufio.NewScanner(resp.Body)
+		var ev sseEvent
+		var data []string
+		for sc.Scan() {
+			line := sc.Text()
+			switch {
+			case line == "":
+				if ev.name != "" {
+					ev.data = strings.Join(data, "\n")
+					events <- ev
+				}
+				ev, data = sseEvent{}, nil
+			case strings.HasPrefix(line, "event: "):
+				ev.name = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				data = append(data, strings.TrimPrefix(line, "data: "))
+			}
+		}
+	}()
+	return events
+}
+
+func recvEvent(t *testing.T, events <-chan sseEvent) sseEvent {
+	t.Helper()
+
+	select {
+	case ev, ok := <-events:
+		if !ok {
+			t.Fatalf("event stream closed")
+		}
+		return ev
+	case <-time.After(2 * time.Second):
+		t.Fatalf("no event within 2s")
+		return sseEvent{}
+	}
+}
+
+func newEventsTestServer(t *testing.T, services []models.Service) (*httptest.Server, *DashboardHandler, *health.Checker) {
+	t.Helper()
+
+	c := health.NewChecker(services, time.Hour, time.Second, time.Second)
+	h, err := NewDashboardHandler("../../web/templates", services, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /dashboard/events", h.Events)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	t.Cleanup(h.CloseStreams)
+	return srv, h, c
+}
+
+func TestEventsPushesOnlyChangedTiles(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(backend.Close)
+
+	services := []models.Service{
+		{Name: "Plex", URL: backend.URL, Category: "Media"},
+		{Name: "Home Assistant", URL: backend.URL, Category: "Automation"},
+	}
+	srv, _, c := newEventsTestServer(t, services)
+	c.CheckNow(t.Context(), "Plex")
+	c.CheckNow(t.Context(), "Home Assistant")
+
+	events := openEvents(t, srv.URL+"/dashboard/events", "")
+
+	c.CheckNow(t.Context(), "Home Assistant")
+
+	ev := recvEvent(t, events)
+	if ev.name != "tile-home-assistant" {
+		t.Fatalf("event %q, want tile-home-assistant", ev.name)
+	}
+	if !strings.Contains(ev.data, `id="svc-body-home-assistant"`) || strings.Contains(ev.data, "svc-body-plex") {
+		t.Fatalf("tile event should carry only the changed tile:\n%s", ev.data)
+	}
+}
+
+func TestEventsKeepOpenHistoryPanel(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(backend.Close)
+
+	services := []models.Service{{Name: "Plex", URL: backend.URL}}
+	srv, h, c := newEventsTestServer(t, services)
+	c.CheckNow(t.Context(), "Plex")
+
+	// The page as the browser has it, with the history panel opened
+	// (the History button swaps service_history into #hist-plex).
+	var page strings.Builder
+	if err := h.tmpl.ExecuteTemplate(&page, "dashboard", h.buildViewData()); err != nil {
+		t.Fatal(err)
+	}
+	const panel = `<div class="aurora-history">open</div>`
+	html := strings.Replace(page.String(), `<div id="hist-plex"></div>`, `<div id="hist-plex">`+panel+`</div>`, 1)
+	if !strings.Contains(html, panel) {
+		t.Fatalf("no history container in tile:\n%s", page.String())
+	}
+
+	events := openEvents(t, srv.URL+"/dashboard/events", "")
+	c.CheckNow(t.Context(), "Plex")
+	ev := recvEvent(t, events)
+	if ev.name != "tile-plex" {
+		t.Fatalf("event %q, want tile-plex", ev.name)
+	}
+
+	// HTMX swaps the pushed fragment over the element listening for it.
+	html = swapOuterHTML(t, html, `sse-swap="tile-plex"`, ev.data)
+	if !strings.Contains(html, panel) {
+		t.Fatalf("pushed tile closed the history panel:\n%s", html)
+	}
+	if !strings.Contains(html, ev.data) {
+		t.Fatalf("pushed tile not applied:\n%s", html)
+	}
+}
+
+// swapOuterHTML replaces the div carrying attr in html with fragment.
+func swapOuterHTML(t *testing.T, html, attr, fragment string) string {
+	t.Helper()
+
+	i := strings.Index(html, attr)
+	if i < 0 {
+		t.Fatalf("no element with %s", attr)
+	}
+	start := strings.LastIndex(html[:i], "<div")
+	depth := 0
+	for j := start; j < len(html); j++ {
+		switch {
+		case strings.HasPrefix(html[j:], "<div"):
+			depth++
+		case strings.HasPrefix(html[j:], "</div>"):
+			depth--
+			if depth == 0 {
+				end := j + len("</div>")
+				return html[:start] + fragment + html[end:]
+			}
+		}
+	}
+	t.Fatalf("unbalanced element with %s", attr)
+	return ""
+}
+
+func TestEventsRefreshOnServiceChange(t *testing.T) {
+	services := []models.Service{
+		{Name: "Router", Type: "tcp", Host: "127.0.0.1", Port: 1},
+		{Name: "NAS", Type: "tcp", Host: "127.0.0.1", Port: 1},
+	}
+	srv, h, c := newEventsTestServer(t, services)
+
+	events := openEvents(t, srv.URL+"/dashboard/events", "")
+
+	h.SetServices(services[:1])
+	c.UpdateServices(services[:1])
+
+	if ev := recvEvent(t, events); ev.name != "refresh" {
+		t.Fatalf("event %q, want refresh", ev.name)
+	}
+}
+
+func TestEventsRefreshOnReconnect(t *testing.T) {
+	srv, _, _ := newEventsTestServer(t, nil)
+
+	events := openEvents(t, srv.URL+"/dashboard/events", "7")
+	if ev := recvEvent(t, events); ev.name != "refresh" {
+		t.Fatalf("event %q, want refresh", ev.name)
+	}
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/health"
+)
+
+// historyLimit caps how many rows the history table shows.
+const historyLimit = 20
+
+// HistoryEntryView is one row of the service history panel.
+type HistoryEntryView struct {
+	CheckedAt   time.Time
+	Status      string
+	StatusClass string
+	RawStatus   string
+	LatencyMs   int64
+	ReasonLabel string
+	ReasonColor string
+	Error       string
+}
+
+// historyViewData is passed into the "service_history" template.
+type historyViewData struct {
+	Name string
+
+	// Strip holds every entry (oldest first) for the status bar;
+	// Rows holds the newest historyLimit entries, newest first.
+	Strip []HistoryEntryView
+	Rows  []HistoryEntryView
+
+	UpCount   int
+	DownCount int
+}
+
+// ServiceHistory renders the recent check history for one service.
+//
+// Query params:
+//   - name:  service name (required)
+//   - since: optional lower bound, either a duration ago ("1h") or RFC3339
+func (h *DashboardHandler) ServiceHistory(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		http.Error(w, "missing name", http.StatusBadRequest)
+		return
+	}
+
+	found := false
+	for _, svc := range h.currentServices() {
+		if svc.Name == name {
+			found = true
+			break
+		}
+	}
+	if !found {
+		http.Error(w, "service not found", http.StatusNotFound)
+		return
+	}
+
+	since, ok := parseSince(r.URL.Query().Get("since"), time.Now())
+	if !ok {
+		http.Error(w, "invalid since", http.StatusBadRequest)
+		return
+	}
+
+	entries := h.checker.History(name, since)
+	for i := range entries {
+		entries[i].Error = h.checker.Redact(entries[i].Error)
+	}
+	data := buildHistoryView(name, entries)
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	if err := h.tmpl.ExecuteTemplate(w, "service_history", data); err != nil {
+		log.Printf("error rendering service history: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+}
+
+// buildHistoryView converts checker history into the template model.
+func buildHistoryView(name string, entries []health.HistoryEntry) historyViewData {
+	data := historyViewData{
+		Name:  name,
+		Strip: make([]HistoryEntryView, 0, len(entries)),
+	}
+
+	for _, e := range entries {
+		label, color := health.ReasonPresentation(e.ReasonClass)
+		data.Strip = append(data.Strip, HistoryEntryView{
+			CheckedAt:   e.CheckedAt,
+			Status:      string(e.Status),
+			StatusClass: bulmaClassForStatus(e.RawStatus),
+			RawStatus:   string(e.RawStatus),
+			LatencyMs:   e.Latency.Milliseconds(),
+			ReasonLabel: label,
+			ReasonColor: color,
+			Error:       e.Error,
+		})
+
+		if e.RawStatus == health.StatusUp {
+			data.UpCount++
+		} else {
+			data.DownCount++
+		}
+	}
+
+	for i := len(data.Strip) - 1; i >= 0 && len(data.Rows) < historyLimit; i-- {
+		data.Rows = append(data.Rows, data.Strip[i])
+	}
+
+	return data
+}
+
+// parseSince accepts "" (no bound), a duration ago ("90m") or an
+// RFC3339 timestamp.
+func parseSince(v string, now time.Time) (time.Time, bool) {
+	if v == "" {
+		return time.Time{}, true
+	}
+	if d, err := time.ParseDuration(v); err == nil {
+		return now.Add(-d), true
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/health"
+)
+
+func TestBuildHistoryView_NewestRowsFirst(t *testing.T) {
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	var entries []health.HistoryEntry
+	for i := 0; i < historyLimit+5; i++ {
+		status := health.StatusUp
+		if i%5 == 0 {
+			status = health.StatusDown
+		}
+		entries = append(entries, health.HistoryEntry{
+			CheckedAt: base.Add(time.Duration(i) * time.Second),
+			Status:    status,
+			RawStatus: status,
+		})
+	}
+
+	v := buildHistoryView("Plex", entries)
+
+	if len(v.Strip) != len(entries) {
+		t.Fatalf("strip len=%d, want %d", len(v.Strip), len(entries))
+	}
+	if len(v.Rows) != historyLimit {
+		t.Fatalf("rows len=%d, want %d", len(v.Rows), historyLimit)
+	}
+	if !v.Rows[0].CheckedAt.Equal(entries[len(entries)-1].CheckedAt) {
+		t.Fatalf("first row should be the newest entry")
+	}
+	if v.DownCount != 5 || v.UpCount != len(entries)-5 {
+		t.Fatalf("up/down=%d/%d, want %d/5", v.UpCount, v.DownCount, len(entries)-5)
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		in     string
+		want   time.Time
+		wantOK bool
+	}{
+		{in: "", want: time.Time{}, wantOK: true},
+		{in: "1h", want: now.Add(-time.Hour), wantOK: true},
+		{in: "2026-01-01T10:00:00Z", want: now.Add(-2 * time.Hour), wantOK: true},
+		{in: "yesterday", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, ok := parseSince(tt.in, now)
+			if ok != tt.wantOK || !got.Equal(tt.want) {
+				t.Fatalf("parseSince(%q) = (%v, %v), want (%v, %v)", tt.in, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/maintenance"
+)
+
+// Silences are managed under /api/v1 like the rest of the JSON API:
+//
+//	GET    /api/v1/silences       silences that have not expired
+//	POST   /api/v1/silences       create one, see apiSilenceRequest
+//	DELETE /api/v1/silences/{id}  lift one early
+
+// silenceDurations are offered by the dashboard's silence form.
+var silenceDurations = []string{"30m", "1h", "2h", "4h", "8h", "24h"}
+
+// silenceFormData is passed into the "silence_form" template.
+type silenceFormData struct {
+	Name      string
+	Category  string
+	Durations []string
+}
+
+// SilenceForm renders the form for silencing one service into the
+// dashboard's silence dialog.
+func (h *DashboardHandler) SilenceForm(w http.ResponseWriter, r *http.Request) {
+	if h.silences == nil {
+		http.Error(w, "silences are not enabled", http.StatusNotFound)
+		return
+	}
+	svc, ok := servicesByName(h.currentServices())[r.URL.Query().Get("name")]
+	if !ok {
+		http.Error(w, "service not found", http.StatusNotFound)
+		return
+	}
+
+	data := silenceFormData{Name: svc.Name, Category: svc.Category, Durations: silenceDurations}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := h.tmpl.ExecuteTemplate(w, "silence_form", data); err != nil {
+		log.Printf("error rendering silence form: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
+
+// Silence handles the silence form (POST /services/silence): it silences
+// the service, or its whole category, and returns the updated tile.
+func (h *DashboardHandler) Silence(w http.ResponseWriter, r *http.Request) {
+	if h.silences == nil {
+		http.Error(w, "silences are not enabled", http.StatusNotFound)
+		return
+	}
+	name := r.FormValue("name")
+	svc, ok := servicesByName(h.currentServices())[name]
+	if !ok {
+		http.Error(w, "service not found", http.StatusNotFound)
+		return
+	}
+	d, err := time.ParseDuration(r.FormValue("duration"))
+	if err != nil || d <= 0 {
+		http.Error(w, "invalid duration", http.StatusBadRequest)
+		return
+	}
+
+	s := maintenance.Silence{
+		Service: svc.Name,
+		By:      r.FormValue("by"),
+		Reason:  r.FormValue("reason"),
+		End:     time.Now().Add(d),
+	}
+	if r.FormValue("scope") == "category" && svc.Category != "" {
+		s.Service, s.Category = "", svc.Category
+	}
+	if _, err := h.silences.AddSilence(s); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	h.checker.RefreshMaintenance()
+
+	h.writeTile(w, name)
+}
+
+// Unsilence lifts a silence from the dashboard (POST /services/unsilence).
+// The tiles follow over the event stream once the fresh checks are in.
+func (h *DashboardHandler) Unsilence(w http.ResponseWriter, r *http.Request) {
+	if h.silences == nil || !h.silences.RemoveSilence(r.FormValue("id")) {
+		http.Error(w, "silence not found", http.StatusNotFound)
+		return
+	}
+	h.checker.RefreshMaintenance()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeTile renders the current tile body of one service, for swapping
+// into its tile.
+func (h *DashboardHandler) writeTile(w http.ResponseWriter, name string) {
+	tile, _, ok := h.findServiceView(name)
+	if !ok {
+		http.Error(w, "service not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := h.tmpl.ExecuteTemplate(w, "service_tile_body", tile); err != nil {
+		log.Printf("error rendering service tile: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
+
+// apiSilenceRequest is the body of POST /api/v1/silences. Either
+// Duration (e.g. "2h") or End sets when the silence expires.
+type apiSilenceRequest struct {
+	Service  string    `json:"service"`
+	Category string    `json:"category"`
+	By       string    `json:"by"`
+	Reason   string    `json:"reason"`
+	Duration string    `json:"duration"`
+	End      time.Time `json:"end"`
+}
+
+// APISilences handles GET /api/v1/silences.
+func (h *DashboardHandler) APISilences(w http.ResponseWriter, r *http.Request) {
+	if h.silences == nil {
+		writeAPIError(w, http.StatusNotFound, "silences are not enabled")
+		return
+	}
+	out := h.silences.Silences()
+	if out == nil {
+		out = []maintenance.Silence{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"silences": out})
+}
+
+// APICreateSilence handles POST /api/v1/silences.
+func (h *DashboardHandler) APICreateSilence(w http.ResponseWriter, r *http.Request) {
+	if h.silences == nil {
+		writeAPIError(w, http.StatusNotFound, "silences are not enabled")
+		return
+	}
+	var req apiSilenceRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64<<10)).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if req.Service != "" {
+		if _, ok := servicesByName(h.currentServices())[req.Service]; !ok {
+			writeAPIError(w, http.StatusNotFound, "service not found")
+			return
+		}
+	}
+
+	end := req.End
+	if req.Duration != "" {
+		d, err := time.ParseDuration(req.Duration)
+		if err != nil || d <= 0 {
+			writeAPIError(w, http.StatusBadRequest, "invalid duration")
+			return
+		}
+		end = time.Now().Add(d)
+	}
+
+	s, err := h.silences.AddSilence(maintenance.Silence{
+		Service:  req.Service,
+		Category: req.Category,
+		By:       req.By,
+		Reason:   req.Reason,
+		End:      end,
+	})
+	if errors.Is(err, maintenance.ErrInvalidSilence) {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.checker.RefreshMaintenance()
+	writeJSON(w, http.StatusCreated, s)
+}
+
+// APIDeleteSilence handles DELETE /api/v1/silences/{id}.
+func (h *DashboardHandler) APIDeleteSilence(w http.ResponseWriter, r *http.Request) {
+	if h.silences == nil || !h.silences.RemoveSilence(r.PathValue("id")) {
+		writeAPIError(w, http.StatusNotFound, "silence not found")
+		return
+	}
+	h.checker.RefreshMaintenance()
+	w.WriteHeader(http.StatusNoContent)
+}
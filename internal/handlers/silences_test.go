@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/health"
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/maintenance"
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/models"
+)
+
+func postJSON(t *testing.T, url, body string, wantStatus int, out any) {
+	t.Helper()
+
+	resp, err := http.Post(url, "application/json", bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != wantStatus {
+		t.Fatalf("POST %s %s: status %d, want %d", url, body, resp.StatusCode, wantStatus)
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("POST %s: decode: %v", url, err)
+		}
+	}
+}
+
+func TestAPISilences(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(backend.Close)
+
+	services := []models.Service{{Name: "NAS", URL: backend.URL, Category: "Storage"}}
+	silences, err := maintenance.New(maintenance.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := health.NewChecker(services, time.Hour, time.Second, time.Second, health.WithMaintenance(silences))
+	c.CheckNow(t.Context(), "NAS")
+
+	h := &DashboardHandler{checker: c, services: services, silences: silences}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/services/{name}", h.APIService)
+	mux.HandleFunc("GET /api/v1/summary", h.APISummary)
+	mux.HandleFunc("GET /api/v1/silences", h.APISilences)
+	mux.HandleFunc("POST /api/v1/silences", h.APICreateSilence)
+	mux.HandleFunc("DELETE /api/v1/silences/{id}", h.APIDeleteSilence)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	postJSON(t, srv.URL+"/api/v1/silences", `{"service":"NAS","reason":"disk swap","duration":"1h"}`, http.StatusBadRequest, nil)
+	postJSON(t, srv.URL+"/api/v1/silences", `{"service":"NAS","by":"alice","reason":"disk swap","duration":"soon"}`, http.StatusBadRequest, nil)
+	postJSON(t, srv.URL+"/api/v1/silences", `{"service":"Nope","by":"alice","reason":"disk swap","duration":"1h"}`, http.StatusNotFound, nil)
+
+	var s maintenance.Silence
+	postJSON(t, srv.URL+"/api/v1/silences", `{"service":"NAS","by":"alice","reason":"disk swap","duration":"1h"}`, http.StatusCreated, &s)
+	if s.ID == "" || s.By != "alice" || time.Until(s.End) < 59*time.Minute {
+		t.Fatalf("created silence = %+v", s)
+	}
+
+	// The silence applies right away, without waiting for a check.
+	var nas APIService
+	getJSON(t, http.MethodGet, srv.URL+"/api/v1/services/NAS", http.StatusOK, &nas)
+	if nas.Status != "MAINTENANCE" || nas.RawStatus != "DOWN" {
+		t.Fatalf("NAS = %+v, want MAINTENANCE (raw DOWN)", nas)
+	}
+	if m := nas.Maintenance; m == nil || m.Name != s.ID || m.By != "alice" || m.Reason != "disk swap" {
+		t.Fatalf("NAS maintenance = %+v", nas.Maintenance)
+	}
+
+	var sum APISummary
+	getJSON(t, http.MethodGet, srv.URL+"/api/v1/summary", http.StatusOK, &sum)
+	if sum.Maintenance != 1 || sum.Down != 0 || sum.Severity != "ok" {
+		t.Fatalf("summary = %+v", sum)
+	}
+
+	var list struct {
+		Silences []maintenance.Silence `json:"silences"`
+	}
+	getJSON(t, http.MethodGet, srv.URL+"/api/v1/silences", http.StatusOK, &list)
+	if len(list.Silences) != 1 || list.Silences[0].ID != s.ID {
+		t.Fatalf("silences = %+v", list.Silences)
+	}
+
+	del := func(id string, want int) {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodDelete, srv.URL+"/api/v1/silences/"+id, nil)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Fatalf("DELETE %s: status %d, want %d", id, resp.StatusCode, want)
+		}
+	}
+	del(s.ID, http.StatusNoContent)
+	del(s.ID, http.StatusNotFound)
+
+	c.CheckNow(t.Context(), "NAS")
+	var after APIService
+	getJSON(t, http.MethodGet, srv.URL+"/api/v1/services/NAS", http.StatusOK, &after)
+	if after.Status != "DOWN" || after.Maintenance != nil {
+		t.Fatalf("NAS after unsilence = %+v", after)
+	}
+}
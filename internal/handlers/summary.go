@@ -7,10 +7,14 @@ import (
 )
 
 type BannerSummary struct {
-	DownCount    int
-	StaleCount   int
-	UnknownCount int
-	UpCount      int
+	DownCount     int
+	StaleCount    int
+	DegradedCount int
+	UnknownCount  int
+	UpCount       int
+
+	MaintenanceCount int
+	UnreachableCount int
 
 	TopReasonLabel string
 	TopReasonCount int
@@ -25,10 +29,16 @@ func SummarizeServices(views []ServiceView) BannerSummary {
 		switch {
 		case v.Status == string(health.StatusDown):
 			s.DownCount++
+		case v.Status == string(health.StatusUnreachable):
+			s.UnreachableCount++
 		case v.IsStale:
 			s.StaleCount++
+		case v.Status == string(health.StatusDegraded):
+			s.DegradedCount++
 		case v.Status == string(health.StatusUnknown):
 			s.UnknownCount++
+		case v.Status == string(health.StatusMaintenance):
+			s.MaintenanceCount++
 		default:
 			s.UpCount++
 		}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"testing"
+	"time"
 
 	"github.com/cyber-mountain-man/aurora-homelab-go/internal/health"
 )
@@ -67,18 +68,39 @@ func TestBuildSummary_SeverityPriority(t *testing.T) {
 			wantSev:  "is-danger",
 			wantDown: 1,
 		},
+		{
+			name:     "unreachable counted with down",
+			views:    []ServiceView{{Status: string(health.StatusDown)}, {Status: string(health.StatusUnreachable)}},
+			wantSev:  "is-danger",
+			wantDown: 1,
+		},
+		{
+			name:    "unreachable when no down",
+			views:   []ServiceView{{Status: string(health.StatusUnreachable)}, {Status: string(health.StatusUp)}},
+			wantSev: "is-warning",
+		},
 		{
 			name:    "stale when no down",
 			views:   []ServiceView{{Status: string(health.StatusUp), IsStale: true}},
 			wantSev: "is-warning",
 			wantSt:  1,
 		},
+		{
+			name:    "degraded when no down or stale",
+			views:   []ServiceView{{Status: string(health.StatusDegraded), Warning: "certificate expires in 3 days"}},
+			wantSev: "is-warning",
+		},
 		{
 			name:    "unknown when no down or stale",
 			views:   []ServiceView{{Status: string(health.StatusUnknown)}},
 			wantSev: "is-dark",
 			wantUnk: 1,
 		},
+		{
+			name:    "maintenance when everything else is up",
+			views:   []ServiceView{{Status: string(health.StatusMaintenance)}, {Status: string(health.StatusUp)}},
+			wantSev: "is-info",
+		},
 		{
 			name:    "all up",
 			views:   []ServiceView{{Status: string(health.StatusUp)}, {Status: string(health.StatusUp)}},
@@ -105,3 +127,47 @@ func TestBuildSummary_SeverityPriority(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildSummary_UptimeRollup(t *testing.T) {
+	views := []ServiceView{
+		{Status: string(health.StatusUp), Category: "Media", month: health.Availability{Uptime: 9 * time.Hour, Downtime: time.Hour, Resolved: 1, RepairTime: time.Hour}},
+		{Status: string(health.StatusUp), Category: "Media", month: health.Availability{Uptime: 10 * time.Hour}},
+		{Status: string(health.StatusUp), Category: "Storage", month: health.Availability{Uptime: 10 * time.Hour}},
+		{Status: string(health.StatusUnknown), Category: "Storage"}, // no data yet
+	}
+
+	s := buildSummary(views)
+
+	if s.UptimeLine != "30d availability: 96.67%" {
+		t.Fatalf("UptimeLine=%q", s.UptimeLine)
+	}
+	if len(s.Categories) != 2 {
+		t.Fatalf("got %d categories, want 2", len(s.Categories))
+	}
+
+	media := s.Categories[0]
+	if media.Category != "Media" || media.Percent != "95.00%" || media.Downtime != "1h0m" || media.MTTR != "1h0m" {
+		t.Fatalf("Media rollup = %+v", media)
+	}
+	if s.Categories[1].Category != "Storage" || s.Categories[1].Percent != "100.00%" {
+		t.Fatalf("Storage rollup = %+v", s.Categories[1])
+	}
+}
+
+func TestSeverityRank_Order(t *testing.T) {
+	ordered := []ServiceView{
+		{Status: string(health.StatusDown)},
+		{Status: string(health.StatusUnreachable)},
+		{Status: string(health.StatusUp), IsStale: true},
+		{Status: string(health.StatusDegraded)},
+		{Status: string(health.StatusUnknown)},
+		{Status: string(health.StatusMaintenance)},
+		{Status: string(health.StatusUp)},
+	}
+
+	for i := 1; i < len(ordered); i++ {
+		if severityRank(ordered[i-1]) >= severityRank(ordered[i]) {
+			t.Fatalf("severityRank(%+v) should sort before %+v", ordered[i-1], ordered[i])
+		}
+	}
+}
@@ -21,7 +21,7 @@ func newDNSBackend(timeout time.Duration) Backend {
 	}
 }
 
-func (b *dnsBackend) Check(svc models.Service) Result {
+func (b *dnsBackend) Check(ctx context.Context, svc models.Service) Result {
 	res := Result{
 		ServiceName: svc.Name,
 		Status:      StatusUnknown,
@@ -34,7 +34,7 @@ func (b *dnsBackend) Check(svc models.Service) Result {
 		return res
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	ctx, cancel := context.WithTimeout(ctx, timeoutFor(svc, b.timeout))
 	defer cancel()
 
 	start := time.Now()
@@ -1,32 +1,39 @@
 package health
 
 import (
+	"context"
+	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/cyber-mountain-man/aurora-homelab-go/internal/models"
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/redact"
 )
 
 // httpBackend implements HTTP-based health checks.
+// The timeout is applied per request so services can override it.
 type httpBackend struct {
-	client *http.Client
+	client   *http.Client
+	timeout  time.Duration
+	patterns regexpCache
 }
 
 func newHTTPBackend(timeout time.Duration) Backend {
 	return &httpBackend{
-		client: &http.Client{
-			Timeout: timeout,
-		},
+		client:  &http.Client{},
+		timeout: timeout,
 	}
 }
 
-func (b *httpBackend) Check(svc models.Service) Result {
+func (b *httpBackend) Check(ctx context.Context, svc models.Service) Result {
 	start := time.Now()
 
 	res := Result{
 		ServiceName: svc.Name,
-		URL:         svc.URL,
+		URL:         redact.URL(svc.URL),
 		Status:      StatusUnknown,
 		CheckedAt:   time.Now(),
 	}
@@ -37,24 +44,94 @@ func (b *httpBackend) Check(svc models.Service) Result {
 		return res
 	}
 
-	resp, err := b.client.Get(svc.URL)
+	ctx, cancel := context.WithTimeout(ctx, timeoutFor(svc, b.timeout))
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, svc.URL, nil)
+	if err != nil {
+		res.Status = StatusDown
+		res.Error = err.Error()
+		return res
+	}
+	for k, v := range svc.Headers {
+		if strings.EqualFold(k, "Host") {
+			req.Host = v
+			continue
+		}
+		req.Header.Set(k, v)
+	}
+	if svc.BasicAuth.IsSet() {
+		req.SetBasicAuth(svc.BasicAuth.Username, svc.BasicAuth.Password)
+	}
+
+	resp, err := b.client.Do(req)
 	if err != nil {
 		res.Status = StatusDown
 		res.Error = err.Error()
 		return res
 	}
 	defer resp.Body.Close()
+
+	exp := svc.Expect
+
+	var body []byte
+	var bodyErr string
+	if exp.HasBodyAssertions() {
+		body, bodyErr = readLimited(resp.Body, exp.MaxBodyBytes)
+	}
 	_, _ = io.Copy(io.Discard, resp.Body)
 
 	res.Latency = time.Since(start)
 	res.CheckedAt = time.Now()
 
-	if resp.StatusCode >= 200 && resp.StatusCode < 400 {
-		res.Status = StatusUp
-	} else {
+	if !statusAllowed(exp, resp.StatusCode) {
 		res.Status = StatusDown
-		res.Error = resp.Status
+		if len(exp.Status) == 0 {
+			res.Error = resp.Status
+		} else {
+			res.Error = fmt.Sprintf("unexpected status %s (want %s)", resp.Status, joinInts(exp.Status))
+		}
+		return res
 	}
 
+	if bodyErr != "" {
+		res.Status = StatusDown
+		res.Error = bodyErr
+		return res
+	}
+
+	if msg := checkBody(exp, body, &b.patterns); msg != "" {
+		res.Status = StatusDown
+		res.Error = msg
+		return res
+	}
+
+	res.Status = StatusUp
 	return res
 }
+
+// readLimited reads at most max bytes from r (defaultMaxBodyBytes when
+// max <= 0). A body larger than the limit is reported as a mismatch
+// rather than silently truncated.
+func readLimited(r io.Reader, max int64) ([]byte, string) {
+	if max <= 0 {
+		max = defaultMaxBodyBytes
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r, max+1))
+	if err != nil {
+		return nil, "http response: read body: " + err.Error()
+	}
+	if int64(len(body)) > max {
+		return nil, fmt.Sprintf("http response: body exceeds max_body_bytes (%d)", max)
+	}
+	return body, ""
+}
+
+func joinInts(xs []int) string {
+	parts := make([]string, len(xs))
+	for i, x := range xs {
+		parts[i] = strconv.Itoa(x)
+	}
+	return strings.Join(parts, ", ")
+}
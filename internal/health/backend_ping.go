@@ -2,6 +2,7 @@
 package health
 
 import (
+	"context"
 	"time"
 
 	"github.com/cyber-mountain-man/aurora-homelab-go/internal/models"
@@ -23,7 +24,7 @@ func newPingBackend(timeout time.Duration) Backend {
 	}
 }
 
-func (b *pingBackend) Check(svc models.Service) Result {
+func (b *pingBackend) Check(ctx context.Context, svc models.Service) Result {
 	res := Result{
 		ServiceName: svc.Name,
 		Status:      StatusUnknown,
@@ -46,7 +47,18 @@ func (b *pingBackend) Check(svc models.Service) Result {
 	// Unprivileged mode: uses UDP fallback where supported.
 	pinger.SetPrivileged(false)
 	pinger.Count = 1
-	pinger.Timeout = b.timeout
+	pinger.Timeout = timeoutFor(svc, b.timeout)
+
+	// pinger.Run has no context support; stop it when ctx is cancelled.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			pinger.Stop()
+		case <-done:
+		}
+	}()
 
 	start := time.Now()
 	if err := pinger.Run(); err != nil {
@@ -56,6 +68,12 @@ func (b *pingBackend) Check(svc models.Service) Result {
 	}
 	stats := pinger.Statistics()
 
+	if err := ctx.Err(); err != nil {
+		res.Status = StatusDown
+		res.Error = err.Error()
+		return res
+	}
+
 	if stats.PacketsRecv < 1 {
 		res.Status = StatusDown
 		res.Error = "no ping reply"
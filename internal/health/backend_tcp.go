@@ -1,6 +1,7 @@
 package health
 
 import (
+	"context"
 	"net"
 	"strconv"
 	"time"
@@ -18,7 +19,7 @@ func newTCPBackend(timeout time.Duration) Backend {
 	}
 }
 
-func (b *tcpBackend) Check(svc models.Service) Result {
+func (b *tcpBackend) Check(ctx context.Context, svc models.Service) Result {
 	res := Result{
 		ServiceName: svc.Name,
 		Status:      StatusUnknown,
@@ -35,7 +36,8 @@ func (b *tcpBackend) Check(svc models.Service) Result {
 	addr := net.JoinHostPort(svc.Host, strconv.Itoa(svc.Port))
 
 	start := time.Now()
-	conn, err := net.DialTimeout("tcp", addr, b.timeout)
+	dialer := &net.Dialer{Timeout: timeoutFor(svc, b.timeout)}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
 	if err != nil {
 		res.Status = StatusDown
 		res.Error = err.Error()
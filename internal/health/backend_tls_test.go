@@ -0,0 +1,178 @@
+package health
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/models"
+)
+
+// tlsService builds a tls Service pointing at an httptest TLS server.
+func tlsService(t *testing.T, srv *httptest.Server) models.Service {
+	t.Helper()
+
+	host, portStr, err := net.SplitHostPort(srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("split addr: %v", err)
+	}
+	port, _ := strconv.Atoi(portStr)
+
+	return models.Service{Name: "tls", Type: "tls", Host: host, Port: port}
+}
+
+// writeCAFile writes cert as a PEM bundle and returns its path.
+func writeCAFile(t *testing.T, cert *x509.Certificate) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("write ca file: %v", err)
+	}
+	return path
+}
+
+// selfSignedCert generates a self-signed certificate for 127.0.0.1.
+func selfSignedCert(t *testing.T, notBefore, notAfter time.Time) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "aurora-test"},
+		Issuer:                pkix.Name{CommonName: "aurora-test"},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func TestTLSBackendValidChain(t *testing.T) {
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	svc := tlsService(t, srv)
+	svc.TLS.CAFile = writeCAFile(t, srv.Certificate())
+
+	res := newTLSBackend(2*time.Second).Check(context.Background(), svc)
+
+	if res.Status != StatusUp {
+		t.Fatalf("status=%q, want %q (error=%q)", res.Status, StatusUp, res.Error)
+	}
+	if res.Warning != "" {
+		t.Fatalf("unexpected warning %q", res.Warning)
+	}
+	if !res.CertNotAfter.Equal(srv.Certificate().NotAfter) {
+		t.Fatalf("CertNotAfter=%v, want %v", res.CertNotAfter, srv.Certificate().NotAfter)
+	}
+}
+
+func TestTLSBackendUnknownAuthority(t *testing.T) {
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	res := newTLSBackend(2*time.Second).Check(context.Background(), tlsService(t, srv))
+
+	if res.Status != StatusDown {
+		t.Fatalf("status=%q, want %q", res.Status, StatusDown)
+	}
+	if rc := ClassifyError(res.Error); rc != ReasonTLS {
+		t.Fatalf("ClassifyError(%q) = %q, want %q", res.Error, rc, ReasonTLS)
+	}
+}
+
+func TestTLSBackendNameMismatch(t *testing.T) {
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	svc := tlsService(t, srv)
+	svc.TLS.CAFile = writeCAFile(t, srv.Certificate())
+	svc.TLS.ServerName = "wrong.example"
+
+	res := newTLSBackend(2*time.Second).Check(context.Background(), svc)
+
+	if res.Status != StatusDown {
+		t.Fatalf("status=%q, want %q", res.Status, StatusDown)
+	}
+	if !strings.Contains(res.Error, "wrong.example") {
+		t.Fatalf("error=%q, want it to mention the requested name", res.Error)
+	}
+}
+
+func TestTLSBackendExpiryWarning(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.NotFoundHandler())
+	cert := selfSignedCert(t, time.Now().Add(-time.Hour), time.Now().Add(10*24*time.Hour))
+	srv.TLS = &tls.Config{Certificates: []tls.Certificate{cert}}
+	srv.StartTLS()
+	defer srv.Close()
+
+	svc := tlsService(t, srv)
+	svc.TLS.CAFile = writeCAFile(t, srv.Certificate())
+	svc.TLS.WarnDays = 30
+
+	res := newTLSBackend(2*time.Second).Check(context.Background(), svc)
+
+	if res.Status != StatusUp {
+		t.Fatalf("status=%q, want %q (error=%q)", res.Status, StatusUp, res.Error)
+	}
+	if !strings.Contains(res.Warning, "expires in 9 days") {
+		t.Fatalf("warning=%q, want expiry warning", res.Warning)
+	}
+	if res.CertIssuer != "aurora-test" {
+		t.Fatalf("CertIssuer=%q, want %q", res.CertIssuer, "aurora-test")
+	}
+}
+
+func TestTLSBackendExpired(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.NotFoundHandler())
+	cert := selfSignedCert(t, time.Now().Add(-48*time.Hour), time.Now().Add(-24*time.Hour))
+	srv.TLS = &tls.Config{Certificates: []tls.Certificate{cert}}
+	srv.StartTLS()
+	defer srv.Close()
+
+	svc := tlsService(t, srv)
+	svc.TLS.CAFile = writeCAFile(t, srv.Certificate())
+
+	res := newTLSBackend(2*time.Second).Check(context.Background(), svc)
+
+	if res.Status != StatusDown {
+		t.Fatalf("status=%q, want %q", res.Status, StatusDown)
+	}
+	if !strings.Contains(res.Error, "certificate expired") {
+		t.Fatalf("error=%q, want expiry error", res.Error)
+	}
+	if rc := ClassifyError(res.Error); rc != ReasonTLS {
+		t.Fatalf("ClassifyError(%q) = %q, want %q", res.Error, rc, ReasonTLS)
+	}
+}
@@ -0,0 +1,53 @@
+package health
+
+import "sync"
+
+// busBuffer is how many values a subscriber may fall behind before
+// values are dropped for it.
+const busBuffer = 64
+
+// bus fans values out to subscribers. Publishing never blocks: a
+// subscriber whose buffer is full misses the value, so a slow consumer
+// cannot hold up the checks that publish.
+type bus[T any] struct {
+	mu   sync.Mutex
+	subs map[<-chan T]chan T
+}
+
+// subscribe registers a new subscriber.
+func (b *bus[T]) subscribe() <-chan T {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.subs == nil {
+		b.subs = make(map[<-chan T]chan T)
+	}
+	ch := make(chan T, busBuffer)
+	b.subs[ch] = ch
+	return ch
+}
+
+// unsubscribe removes a subscriber and closes its channel. Unknown
+// channels are ignored.
+func (b *bus[T]) unsubscribe(ch <-chan T) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if c, ok := b.subs[ch]; ok {
+		delete(b.subs, ch)
+		close(c)
+	}
+}
+
+// publish sends v to every subscriber that has room for it.
+func (b *bus[T]) publish(v T) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, c := range b.subs {
+		select {
+		case c <- v:
+		default:
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package health
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/models"
+)
+
+func TestCheckerPublishesUpdates(t *testing.T) {
+	svc := models.Service{Name: "NAS", Type: "tcp", FailureThreshold: 1}
+	c := NewChecker([]models.Service{svc}, time.Hour, time.Second, time.Second)
+	c.backends["tcp"] = newFlakyBackend(1)
+
+	updates := c.SubscribeUpdates()
+
+	c.CheckNow(context.Background(), "NAS")
+	if u := recvUpdate(t, updates); u != (Update{Service: "NAS", Status: StatusDown}) {
+		t.Fatalf("update = %+v, want NAS DOWN", u)
+	}
+	c.CheckNow(context.Background(), "NAS")
+	if u := recvUpdate(t, updates); u != (Update{Service: "NAS", Status: StatusUp}) {
+		t.Fatalf("update = %+v, want NAS UP", u)
+	}
+
+	c.UpdateServices(nil)
+	if u := recvUpdate(t, updates); u != (Update{}) {
+		t.Fatalf("update = %+v, want a service set update", u)
+	}
+
+	c.UnsubscribeUpdates(updates)
+	if _, ok := <-updates; ok {
+		t.Fatalf("channel should be closed after UnsubscribeUpdates")
+	}
+}
+
+func TestBusDropsWhenSubscriberFallsBehind(t *testing.T) {
+	var b bus[int]
+	ch := b.subscribe()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 2*busBuffer; i++ {
+			b.publish(i)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("publish blocked on a full subscriber")
+	}
+
+	if n := len(ch); n != busBuffer {
+		t.Fatalf("buffered %d values, want %d", n, busBuffer)
+	}
+	if v := <-ch; v != 0 {
+		t.Fatalf("first value = %d, want 0 (later values are the ones dropped)", v)
+	}
+}
+
+func recvUpdate(t *testing.T, ch <-chan Update) Update {
+	t.Helper()
+
+	select {
+	case u := <-ch:
+		return u
+	case <-time.After(2 * time.Second):
+		t.Fatalf("no update within 2s")
+		return Update{}
+	}
+}
@@ -1,104 +1,391 @@
 package health
 
 import (
+	"context"
+	"fmt"
+	"log"
+	"reflect"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/cyber-mountain-man/aurora-homelab-go/internal/models"
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/redact"
 )
 
 // Status represents the health status of a service.
 type Status string
 
 const (
-	StatusUnknown Status = "UNKNOWN"
-	StatusUp      Status = "UP"
-	StatusDown    Status = "DOWN"
-	StatusStale   Status = "STALE"
+	StatusUnknown  Status = "UNKNOWN"
+	StatusUp       Status = "UP"
+	StatusDown     Status = "DOWN"
+	StatusStale    Status = "STALE"
+	StatusDegraded Status = "DEGRADED" // passing, but slow or with a warning
+
+	// StatusMaintenance marks a service in a maintenance window or
+	// silenced by hand; RawStatus still has the checked status.
+	StatusMaintenance Status = "MAINTENANCE"
+
+	// StatusUnreachable marks a failing service that depends on a
+	// failed one; Result.RootCause names the root-most failure.
+	StatusUnreachable Status = "UNREACHABLE"
 )
 
+// IsUp reports whether s means the service is serving (UP or DEGRADED).
+func (s Status) IsUp() bool {
+	return s == StatusUp || s == StatusDegraded
+}
+
 // Result holds the outcome of a single health check.
+//
+// Status is the debounced state shown to users: it only flips after
+// the service's failure/success threshold is reached. RawStatus is
+// what the last check actually returned.
 type Result struct {
 	ServiceName string
 	URL         string
 	Status      Status
+	RawStatus   Status
 	Latency     time.Duration
 	CheckedAt   time.Time
 	Error       string // optional: last error message
+	Attempts    int    // how many attempts the check took (1 + retries used)
+
+	ConsecutiveFailures  int
+	ConsecutiveSuccesses int
+
+	// Restored is true when the result was loaded from the Store on
+	// startup and has not been replaced by a fresh check yet.
+	Restored bool
+
+	// Warning is set when the check passed but something needs
+	// attention (a certificate close to expiry, latency over the
+	// service's threshold). Such results are stored as DEGRADED.
+	Warning string
+
+	// TLS certificate details (tls checks only).
+	CertNotAfter time.Time
+	CertIssuer   string
+
+	// Maintenance is the window or silence behind a MAINTENANCE status.
+	Maintenance *Maintenance `json:",omitempty"`
+
+	// RootCause is the failed service behind an UNREACHABLE status.
+	RootCause string `json:",omitempty"`
+}
+
+// Update announces a change to what the Checker reports: a new result
+// for Service or, when Service is empty, a new set of services (config
+// reload, state restored on startup). Updates are re-render hints for
+// views; status changes are published as Events (see Subscribe).
+type Update struct {
+	Service string
+	Status  Status // debounced status after the check
 }
 
 // Backend defines a pluggable health check implementation.
 // Different backends can check HTTP, TCP, ICMP, etc.
+// Check must return promptly once ctx is cancelled.
 type Backend interface {
-	Check(svc models.Service) Result
+	Check(ctx context.Context, svc models.Service) Result
 }
 
+// CheckTypes are the service types the Checker has backends for. An
+// empty models.Service.Type means "http".
+var CheckTypes = []string{"http", "tcp", "dns", "ping", "tls"}
+
+// defaultRetryDelay is the pause between a failed attempt and its retry.
+const defaultRetryDelay = 500 * time.Millisecond
+
+// defaultRetention is how long transitions are kept in memory (and in
+// the Store) when WithStore is given no retention.
+const defaultRetention = 30 * 24 * time.Hour
+
+// compactEvery is how often the Store is compacted.
+const compactEvery = time.Hour
+
 // Checker periodically checks the health of configured services.
+// Each service runs on its own schedule (models.Service.Interval),
+// falling back to the Checker's default interval.
 type Checker struct {
 	mu       sync.RWMutex
 	results  map[string]Result
+	history  map[string]*historyRing
 	services []models.Service
 
+	// transitions holds status changes per service, pruned to retention.
+	transitions map[string][]Transition
+
+	// latencies holds recent latency samples of passing checks.
+	latencies map[string]*latencyWindow
+
+	// inFlight marks services whose scheduled check is queued or
+	// running, or that verifyDependencies is checking.
+	inFlight map[string]bool
+
+	// schedules cancels each service's scheduling loop (and its
+	// in-flight check) while Run is active.
+	schedules map[string]context.CancelFunc
+	runCtx    context.Context
+	jobs      chan job
+	runWG     *sync.WaitGroup
+
 	backends map[string]Backend
 
-	interval time.Duration
+	interval      time.Duration
+	retryDelay    time.Duration
+	historyDepth  int
+	latencyWindow int
+	workers       int
+	jitter        float64
+
+	store     Store
+	retention time.Duration
+
+	redactor    *redact.Redactor
+	observer    Observer
+	maintenance MaintenanceSchedule
+	deps        depGraph
+
+	// events carries status changes, which is all notifications and
+	// metrics act on. updates is a separate, coarser bus for views
+	// (SSE) that must re-render after every result, including ones
+	// that change only latency or the check time, and after reloads;
+	// folding those into Event would make every consumer filter out
+	// non-changes. Persistence is not a subscriber: record writes to
+	// the store directly, as a bus drops events for slow subscribers.
+	events  bus[Event]
+	updates bus[Update]
+
+	// running counts checks in progress; queued counts scheduled checks
+	// waiting for a worker. Both feed Stats.
+	running atomic.Int64
+	queued  atomic.Int64
+
+	// lifecycle for Start/Stop
+	cancel context.CancelFunc
+	done   chan struct{}
 }
 
 // NewChecker creates a new Checker.
-// interval: how often to run checks (e.g., 30s)
-// httpTimeout: HTTP timeout per request (e.g., 3s)
-// tcpTimeout: TCP dial timeout (e.g., 2s)
-func NewChecker(services []models.Service, interval, httpTimeout, tcpTimeout time.Duration) *Checker {
+// interval: default check interval for services without their own (e.g., 30s)
+// httpTimeout: default HTTP timeout per request (e.g., 3s)
+// tcpTimeout: default TCP dial timeout (e.g., 2s)
+func NewChecker(services []models.Service, interval, httpTimeout, tcpTimeout time.Duration, opts ...Option) *Checker {
 	backends := map[string]Backend{
 		"http": newHTTPBackend(httpTimeout),
 		"tcp":  newTCPBackend(tcpTimeout),
 		"dns":  newDNSBackend(httpTimeout), // reuse HTTP timeout for DNS
 		"ping": newPingBackend(tcpTimeout), // reuse TCP timeout for ping
+		"tls":  newTLSBackend(httpTimeout), // handshake + cert checks share the HTTP timeout
 	}
 
-	return &Checker{
-		results:  make(map[string]Result),
-		services: services,
-		backends: backends,
-		interval: interval,
+	c := &Checker{
+		results:       make(map[string]Result),
+		history:       make(map[string]*historyRing),
+		services:      services,
+		deps:          newDepGraph(services),
+		backends:      backends,
+		interval:      interval,
+		retryDelay:    defaultRetryDelay,
+		historyDepth:  defaultHistoryDepth,
+		transitions:   make(map[string][]Transition),
+		latencies:     make(map[string]*latencyWindow),
+		latencyWindow: defaultLatencyWindow,
+		inFlight:      make(map[string]bool),
+		schedules:     make(map[string]context.CancelFunc),
+		workers:       defaultWorkers,
+		jitter:        defaultJitter,
+		retention:     defaultRetention,
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
 }
 
-// Start begins periodic health checks in a background goroutine.
-// It also runs one initial check immediately.
-func (c *Checker) Start() {
-	c.checkAll()
+// Run performs periodic health checks until ctx is cancelled. Each
+// service is scheduled on its own interval (with jitter) and checks
+// are executed by a bounded pool of workers; a service whose previous
+// check is still queued or running is skipped rather than stacked.
+//
+// With a Store configured, the last known state is restored first
+// (results are marked Restored, and the time since the last stored
+// check is a gap that availability leaves out) and compaction runs in
+// the background.
+//
+// When ctx is cancelled, in-flight checks are cancelled too and Run
+// returns once they have all finished.
+func (c *Checker) Run(ctx context.Context) {
+	var wg sync.WaitGroup
 
-	go func() {
-		ticker := time.NewTicker(c.interval)
-		defer ticker.Stop()
+	if c.store != nil {
+		if err := c.restore(); err != nil {
+			log.Printf("warning: could not restore health state: %v", err)
+		}
+		c.updates.publish(Update{})
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.compactLoop(ctx)
+		}()
+	}
+
+	if c.maintenance != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.maintenanceLoop(ctx)
+		}()
+	}
+
+	jobs := make(chan job, c.workers)
+
+	for i := 0; i < c.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.worker(ctx, jobs)
+		}()
+	}
+
+	c.mu.Lock()
+	c.runCtx, c.jobs, c.runWG = ctx, jobs, &wg
+	for _, svc := range c.services {
+		c.startScheduleLocked(svc)
+	}
+	c.mu.Unlock()
 
-		for range ticker.C {
-			c.checkAll()
+	<-ctx.Done()
+
+	// Stop UpdateServices from starting new loops before waiting.
+	c.mu.Lock()
+	c.runCtx, c.jobs, c.runWG = nil, nil, nil
+	c.schedules = make(map[string]context.CancelFunc)
+	c.mu.Unlock()
+
+	wg.Wait()
+
+	// Drop checks that were queued but never picked up by a worker.
+	for {
+		select {
+		case j := <-jobs:
+			c.queued.Add(-1)
+			c.finish(j.svc.Name)
+		default:
+			return
 		}
+	}
+}
+
+// Start runs the Checker in the background until Stop is called.
+func (c *Checker) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	c.mu.Lock()
+	c.cancel = cancel
+	c.done = done
+	c.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		c.Run(ctx)
 	}()
 }
 
+// Stop cancels the checks started by Start and waits for them to
+// finish. It is a no-op if the Checker was not started.
+func (c *Checker) Stop() {
+	c.mu.Lock()
+	cancel, done := c.cancel, c.done
+	c.cancel, c.done = nil, nil
+	c.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+}
+
 // CheckNow triggers an immediate health check for a single service by name.
 // It runs synchronously so the caller can return updated UI right away.
-func (c *Checker) CheckNow(name string) bool {
-	// Find the service
+func (c *Checker) CheckNow(ctx context.Context, name string) bool {
+	svc, ok := c.lookup(name)
+	if !ok {
+		return false
+	}
+	c.checkOne(ctx, svc)
+	return true
+}
+
+// Services returns the currently configured services.
+func (c *Checker) Services() []models.Service {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]models.Service(nil), c.services...)
+}
+
+// lookup finds a configured service by name.
+func (c *Checker) lookup(name string) (models.Service, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.lookupLocked(name)
+}
+
+// lookupLocked is lookup for callers already holding c.mu.
+func (c *Checker) lookupLocked(name string) (models.Service, bool) {
 	for _, svc := range c.services {
 		if svc.Name == name {
-			backend := c.getBackend(svc.Type)
-			res := backend.Check(svc)
-			c.storeResult(res)
-			return true
+			return svc, true
 		}
 	}
-	return false
+	return models.Service{}, false
 }
 
-// checkAll launches a check for each service.
-func (c *Checker) checkAll() {
+// UpdateServices atomically replaces the configured services, e.g. after
+// a config reload. Unchanged services keep their schedule and state;
+// changed services are rescheduled but keep their results and history;
+// removed services have their schedule and in-flight check cancelled
+// and their state dropped.
+func (c *Checker) UpdateServices(services []models.Service) {
+	defer c.updates.publish(Update{})
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	old := make(map[string]models.Service, len(c.services))
 	for _, svc := range c.services {
-		// Launch each check in its own goroutine.
-		go c.checkOne(svc)
+		old[svc.Name] = svc
+	}
+	c.services = services
+	c.deps = newDepGraph(services)
+
+	for _, svc := range services {
+		prev, existed := old[svc.Name]
+		delete(old, svc.Name)
+
+		if existed && reflect.DeepEqual(prev, svc) {
+			continue
+		}
+		if cancel, ok := c.schedules[svc.Name]; ok {
+			cancel()
+		}
+		c.startScheduleLocked(svc)
+	}
+
+	// Whatever is left in old was removed from the config.
+	for name := range old {
+		if cancel, ok := c.schedules[name]; ok {
+			cancel()
+			delete(c.schedules, name)
+		}
+		delete(c.results, name)
+		delete(c.history, name)
+		delete(c.transitions, name)
+		delete(c.latencies, name)
 	}
 }
 
@@ -114,18 +401,324 @@ func (c *Checker) getBackend(svcType string) Backend {
 	return c.backends["http"]
 }
 
-// checkOne performs a single health check using the appropriate backend.
-func (c *Checker) checkOne(svc models.Service) {
+// checkOne performs a single health check using the appropriate backend,
+// retrying up to svc.Retries times before reporting a failure.
+// Results of checks interrupted by ctx cancellation are discarded.
+func (c *Checker) checkOne(ctx context.Context, svc models.Service) {
 	backend := c.getBackend(svc.Type)
-	res := backend.Check(svc)
-	c.storeResult(res)
+
+	c.running.Add(1)
+	defer c.running.Add(-1)
+	start := time.Now()
+
+	var res Result
+	for attempt := 1; ; attempt++ {
+		res = backend.Check(ctx, svc)
+		res.Attempts = attempt
+
+		if ctx.Err() != nil {
+			return
+		}
+		if res.Status == StatusUp || attempt > svc.Retries {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(c.retryDelay):
+		}
+	}
+
+	// Before declaring a new outage, make sure what the service depends
+	// on is still up, so the failure is attributed to the right place.
+	if res.Status != StatusUp && len(svc.DependsOn) > 0 {
+		c.verifyDependencies(ctx, svc)
+		if ctx.Err() != nil {
+			return
+		}
+	}
+
+	ev := c.storeResult(svc, res)
+
+	if c.observer != nil {
+		c.observer.ObserveCheck(svc, res, time.Since(start))
+	}
+	if ev != nil {
+		c.refreshDependents(ctx, svc.Name)
+	}
+}
+
+// storeResult safely writes a Result into the map, applying flap
+// suppression against the previously stored result, and persists it.
+// It returns the status change the result caused, if any.
+func (c *Checker) storeResult(svc models.Service, res Result) *Event {
+	res.Error = c.Redact(res.Error)
+	res.Warning = c.Redact(res.Warning)
+	res.URL = c.Redact(res.URL)
+
+	res, ev, ok := c.applyResult(svc, res)
+	if !ok {
+		return nil
+	}
+
+	if res.Error != "" {
+		log.Printf("debug: %s %s in %s: %s", res.ServiceName, res.RawStatus, res.Latency, res.Error)
+	} else {
+		log.Printf("debug: %s %s in %s", res.ServiceName, res.RawStatus, res.Latency)
+	}
+	c.record(res, ev)
+	return ev
+}
+
+// record announces a stored result and the status change it caused,
+// if any, and persists both.
+func (c *Checker) record(res Result, ev *Event) {
+	if ev != nil {
+		if ev.Old != StatusUnknown {
+			log.Printf("%s: %s -> %s", res.ServiceName, ev.Old, ev.New)
+		}
+		c.events.publish(*ev)
+	}
+	c.updates.publish(Update{Service: res.ServiceName, Status: res.Status})
+
+	if c.store == nil {
+		return
+	}
+	if err := c.store.SaveResult(res); err != nil {
+		log.Printf("warning: could not persist result for %s: %v", res.ServiceName, err)
+	}
+	if ev != nil {
+		if err := c.store.SaveTransition(ev.Transition()); err != nil {
+			log.Printf("warning: could not persist transition for %s: %v", res.ServiceName, err)
+		}
+	}
 }
 
-// storeResult safely writes a Result into the map.
-func (c *Checker) storeResult(res Result) {
+// applyResult updates in-memory state under the lock and returns the
+// stored result plus the status change it caused, if any. Results for
+// services that are no longer configured are dropped (ok is false).
+func (c *Checker) applyResult(svc models.Service, res Result) (_ Result, _ *Event, ok bool) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+
+	if _, ok := c.lookupLocked(svc.Name); !ok {
+		return res, nil, false
+	}
+
+	prev, ok := c.results[res.ServiceName]
+	if !ok {
+		prev.Status = StatusUnknown
+	}
+
+	res.RawStatus = res.Status
+	if res.RawStatus == StatusUp {
+		res.ConsecutiveSuccesses = prev.ConsecutiveSuccesses + 1
+	} else {
+		res.ConsecutiveFailures = prev.ConsecutiveFailures + 1
+	}
+	res.Status = debounce(prev.Status, res, svc)
+	if res.Status == StatusUnreachable && res.RootCause == "" {
+		res.RootCause = prev.RootCause // held while recovering
+	}
+
+	if res.RawStatus == StatusUp {
+		lw, ok := c.latencies[res.ServiceName]
+		if !ok {
+			lw = newLatencyWindow(c.latencyWindow)
+			c.latencies[res.ServiceName] = lw
+		}
+		lw.add(res.Latency)
+
+		if res.Warning == "" {
+			res.Warning = latencyWarning(svc, res.Latency, lw.stats())
+		}
+	}
+	if res.Status == StatusUp && res.Warning != "" {
+		res.Status = StatusDegraded
+	}
+	res = c.applyDependenciesLocked(svc, res)
+	res = c.applyMaintenanceLocked(svc, res)
+
 	c.results[res.ServiceName] = res
+
+	h, ok := c.history[res.ServiceName]
+	if !ok {
+		h = newHistoryRing(c.historyDepth)
+		c.history[res.ServiceName] = h
+	}
+	h.push(NewHistoryEntry(res))
+
+	if res.Status == prev.Status {
+		return res, nil, true
+	}
+	return res, c.transitionLocked(svc, prev.Status, res, res.CheckedAt), true
+}
+
+// transitionLocked records the change from old to res.Status at at and
+// returns its Event.
+func (c *Checker) transitionLocked(svc models.Service, old Status, res Result, at time.Time) *Event {
+	ev := Event{
+		Service: svc,
+		Old:     old,
+		New:     res.Status,
+		At:      at,
+	}
+	if !res.Status.IsUp() && res.Status != StatusMaintenance {
+		ev.Error = res.Error
+		ev.Reason = ClassifyError(res.Error)
+	}
+	switch res.Status {
+	case StatusDown:
+		ev.Affected = c.affectedLocked(svc.Name)
+	case StatusUnreachable:
+		ev.RootCause = res.RootCause
+	}
+	ts := c.transitions[svc.Name]
+	if len(ts) > 0 {
+		ev.Duration = at.Sub(stateSince(ts))
+	}
+
+	c.transitions[svc.Name] = pruneTransitions(
+		append(ts, ev.Transition()),
+		at.Add(-c.retention),
+	)
+	return &ev
+}
+
+// latencyWarning returns a warning when the latest latency or the
+// window's p95 exceeds the service's thresholds.
+func latencyWarning(svc models.Service, latest time.Duration, st LatencyStats) string {
+	if svc.LatencyWarn > 0 && latest > svc.LatencyWarn {
+		return fmt.Sprintf("latency %s exceeds %s", latest.Round(time.Millisecond), svc.LatencyWarn)
+	}
+	if svc.LatencyWarnP95 > 0 && st.P95 > svc.LatencyWarnP95 {
+		return fmt.Sprintf("p95 latency %s exceeds %s", st.P95.Round(time.Millisecond), svc.LatencyWarnP95)
+	}
+	return ""
+}
+
+// pruneTransitions drops transitions before cutoff but keeps the last
+// one before it, since it defines the state at the start of the window.
+func pruneTransitions(ts []Transition, cutoff time.Time) []Transition {
+	i := 0
+	for i+1 < len(ts) && !ts[i+1].At.After(cutoff) {
+		i++
+	}
+	return ts[i:]
+}
+
+// restore loads the last known state from the Store for every
+// configured service. The time Aurora was not running is recorded as
+// a gap (UNKNOWN from the last stored check until now), so it counts
+// neither as up nor as down.
+func (c *Checker) restore() error {
+	st, err := c.store.Load()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var gaps []Transition
+
+	c.mu.Lock()
+	cutoff := now.Add(-c.retention)
+	for _, svc := range c.services {
+		res, ok := st.Results[svc.Name]
+		if ok {
+			res.Restored = true
+			c.results[svc.Name] = res
+		}
+
+		if entries := st.History[svc.Name]; len(entries) > 0 {
+			h := newHistoryRing(c.historyDepth)
+			for _, e := range entries {
+				h.push(e)
+			}
+			c.history[svc.Name] = h
+		}
+
+		ts := st.Transitions[svc.Name]
+		if ok && len(ts) > 0 && ts[len(ts)-1].To != StatusUnknown {
+			last := ts[len(ts)-1]
+			lastSeen := last.At
+			if res.CheckedAt.After(lastSeen) {
+				lastSeen = res.CheckedAt
+			}
+			gap := []Transition{
+				{ServiceName: svc.Name, From: last.To, To: StatusUnknown, At: lastSeen},
+				{ServiceName: svc.Name, From: StatusUnknown, To: last.To, At: now},
+			}
+			ts = append(ts, gap...)
+			gaps = append(gaps, gap...)
+		}
+		if len(ts) > 0 {
+			c.transitions[svc.Name] = pruneTransitions(ts, cutoff)
+		}
+	}
+	c.mu.Unlock()
+
+	for _, t := range gaps {
+		if err := c.store.SaveTransition(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stateSince returns when the state ts ends in began, looking through
+// the gaps restore records for restarts.
+func stateSince(ts []Transition) time.Time {
+	i := len(ts) - 1
+	for i >= 2 && ts[i].From == StatusUnknown && ts[i-1].To == StatusUnknown && ts[i-2].To == ts[i].To {
+		i -= 2
+	}
+	return ts[i].At
+}
+
+// compactLoop compacts the Store now and then every compactEvery
+// until ctx is cancelled.
+func (c *Checker) compactLoop(ctx context.Context) {
+	ticker := time.NewTicker(compactEvery)
+	defer ticker.Stop()
+
+	for {
+		if err := c.store.Compact(time.Now().Add(-c.retention)); err != nil {
+			log.Printf("warning: could not compact health store: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// debounce decides the displayed status given the previous displayed
+// status and the consecutive counters on the new result.
+// From UNKNOWN (first check) the raw status is taken as-is. A DOWN or
+// UNREACHABLE service stays as it was until it has passed enough
+// checks, so one recovering with its root cause is not shown DOWN.
+func debounce(prev Status, res Result, svc models.Service) Status {
+	switch {
+	case prev.IsUp() && res.RawStatus != StatusUp:
+		if res.ConsecutiveFailures < svc.FailureThreshold {
+			return StatusUp
+		}
+	case (prev == StatusDown || prev == StatusUnreachable) && res.RawStatus == StatusUp:
+		if res.ConsecutiveSuccesses < svc.SuccessThreshold {
+			return prev
+		}
+	}
+	return res.RawStatus
+}
+
+// Redact masks configured secrets in s (see WithRedactor). Results are
+// redacted before they are stored; callers showing other service data,
+// such as a configured URL, should pass it through Redact too.
+func (c *Checker) Redact(s string) string {
+	return c.redactor.String(s)
 }
 
 // Snapshot returns a copy of the last known results map.
@@ -140,7 +733,78 @@ func (c *Checker) Snapshot() map[string]Result {
 	return out
 }
 
-// interval returns the health check interval.
+// SubscribeUpdates returns a channel that receives an Update after
+// every stored result and every change to the service set. Updates are
+// dropped rather than queued when the subscriber falls behind, so treat
+// one as a hint to re-read state (Snapshot) rather than as the state.
+// Release the channel with UnsubscribeUpdates.
+func (c *Checker) SubscribeUpdates() <-chan Update {
+	return c.updates.subscribe()
+}
+
+// UnsubscribeUpdates stops and closes a channel from SubscribeUpdates.
+func (c *Checker) UnsubscribeUpdates(ch <-chan Update) {
+	c.updates.unsubscribe(ch)
+}
+
+// History returns the recorded results for a service checked at or
+// after since (zero means all), oldest first. It returns nil for
+// services that have not been checked yet.
+func (c *Checker) History(name string, since time.Time) []HistoryEntry {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	h, ok := c.history[name]
+	if !ok {
+		return nil
+	}
+	return h.since(since)
+}
+
+// LatencyStats returns latency percentiles over the recent passing
+// checks of a service (zero Samples if there are none).
+func (c *Checker) LatencyStats(name string) LatencyStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	lw, ok := c.latencies[name]
+	if !ok {
+		return LatencyStats{}
+	}
+	return lw.stats()
+}
+
+// Transitions returns recorded status changes for a service at or
+// after since (zero means all), oldest first. The last transition
+// before since is included too, so callers know the state at since.
+func (c *Checker) Transitions(name string, since time.Time) []Transition {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	ts := c.transitions[name]
+	if !since.IsZero() {
+		ts = pruneTransitions(ts, since)
+	}
+	return append([]Transition(nil), ts...)
+}
+
+// Interval returns the default health check interval.
 func (c *Checker) Interval() time.Duration {
 	return c.interval
 }
+
+// IntervalFor returns the effective check interval for svc.
+func (c *Checker) IntervalFor(svc models.Service) time.Duration {
+	if svc.Interval > 0 {
+		return svc.Interval
+	}
+	return c.interval
+}
+
+// timeoutFor returns the service's own timeout, or def when unset.
+func timeoutFor(svc models.Service, def time.Duration) time.Duration {
+	if svc.Timeout > 0 {
+		return svc.Timeout
+	}
+	return def
+}
@@ -1,6 +1,8 @@
 package health
 
 import (
+	"context"
+	"sync"
 	"testing"
 	"time"
 
@@ -11,7 +13,7 @@ type stubBackend struct {
 	res Result
 }
 
-func (s stubBackend) Check(svc models.Service) Result {
+func (s stubBackend) Check(_ context.Context, svc models.Service) Result {
 	r := s.res
 	r.ServiceName = svc.Name
 	r.CheckedAt = time.Now()
@@ -34,7 +36,7 @@ func TestCheckerCheckNowStoresResult(t *testing.T) {
 		},
 	}
 
-	ok := c.CheckNow("Google")
+	ok := c.CheckNow(context.Background(), "Google")
 	if !ok {
 		t.Fatalf("CheckNow returned false; expected true")
 	}
@@ -54,7 +56,210 @@ func TestCheckerCheckNowStoresResult(t *testing.T) {
 
 func TestCheckerCheckNowMissingService(t *testing.T) {
 	c := NewChecker(nil, 30*time.Second, 3*time.Second, 2*time.Second)
-	if ok := c.CheckNow("does-not-exist"); ok {
+	if ok := c.CheckNow(context.Background(), "does-not-exist"); ok {
 		t.Fatalf("expected CheckNow to return false for missing service")
 	}
 }
+
+// flakyBackend fails the first `failures` calls per service, then succeeds.
+type flakyBackend struct {
+	mu       sync.Mutex
+	failures int
+	calls    map[string]int
+}
+
+func newFlakyBackend(failures int) *flakyBackend {
+	return &flakyBackend{failures: failures, calls: make(map[string]int)}
+}
+
+func (f *flakyBackend) Check(_ context.Context, svc models.Service) Result {
+	f.mu.Lock()
+	f.calls[svc.Name]++
+	n := f.calls[svc.Name]
+	f.mu.Unlock()
+
+	r := Result{ServiceName: svc.Name, CheckedAt: time.Now(), Status: StatusUp}
+	if n <= f.failures {
+		r.Status = StatusDown
+		r.Error = "connect: connection refused"
+	}
+	return r
+}
+
+func (f *flakyBackend) count(name string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[name]
+}
+
+func TestCheckerRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		retries      int
+		failures     int
+		wantStatus   Status
+		wantAttempts int
+	}{
+		{name: "no retries fails fast", retries: 0, failures: 1, wantStatus: StatusDown, wantAttempts: 1},
+		{name: "retry recovers", retries: 2, failures: 2, wantStatus: StatusUp, wantAttempts: 3},
+		{name: "retries exhausted", retries: 2, failures: 5, wantStatus: StatusDown, wantAttempts: 3},
+		{name: "up on first attempt", retries: 3, failures: 0, wantStatus: StatusUp, wantAttempts: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			services := []models.Service{{Name: "NAS", Type: "tcp", Retries: tt.retries}}
+			c := NewChecker(services, 30*time.Second, 3*time.Second, 2*time.Second)
+			c.retryDelay = 0
+
+			fb := newFlakyBackend(tt.failures)
+			c.backends["tcp"] = fb
+
+			c.CheckNow(context.Background(), "NAS")
+
+			got := c.Snapshot()["NAS"]
+			if got.Status != tt.wantStatus {
+				t.Fatalf("status=%q, want %q", got.Status, tt.wantStatus)
+			}
+			if got.Attempts != tt.wantAttempts || fb.count("NAS") != tt.wantAttempts {
+				t.Fatalf("attempts=%d calls=%d, want %d", got.Attempts, fb.count("NAS"), tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestCheckerPerServiceInterval(t *testing.T) {
+	services := []models.Service{
+		{Name: "Router", Type: "tcp", Interval: 10 * time.Millisecond},
+		{Name: "NAS", Type: "tcp", Interval: time.Hour},
+	}
+	c := NewChecker(services, 30*time.Second, 3*time.Second, 2*time.Second, WithJitter(0))
+
+	fb := newFlakyBackend(0)
+	c.backends["tcp"] = fb
+
+	c.Start()
+	time.Sleep(100 * time.Millisecond)
+	c.Stop()
+
+	if n := fb.count("NAS"); n != 1 {
+		t.Fatalf("NAS checked %d times, want exactly the initial check", n)
+	}
+	if n := fb.count("Router"); n < 3 {
+		t.Fatalf("Router checked %d times, want several", n)
+	}
+
+	if got := c.IntervalFor(services[1]); got != time.Hour {
+		t.Fatalf("IntervalFor(NAS)=%v, want 1h", got)
+	}
+	if got := c.IntervalFor(models.Service{Name: "default"}); got != 30*time.Second {
+		t.Fatalf("IntervalFor(default)=%v, want 30s", got)
+	}
+}
+
+func TestCheckerFlapSuppression(t *testing.T) {
+	svc := models.Service{Name: "Router", Type: "tcp", FailureThreshold: 3, SuccessThreshold: 2}
+	c := NewChecker([]models.Service{svc}, 30*time.Second, 3*time.Second, 2*time.Second)
+
+	steps := []struct {
+		raw       Status
+		want      Status
+		wantFails int
+		wantOKs   int
+	}{
+		{raw: StatusUp, want: StatusUp, wantOKs: 1}, // first result is taken as-is
+		{raw: StatusDown, want: StatusUp, wantFails: 1},
+		{raw: StatusDown, want: StatusUp, wantFails: 2},
+		{raw: StatusUp, want: StatusUp, wantOKs: 1}, // streak broken
+		{raw: StatusDown, want: StatusUp, wantFails: 1},
+		{raw: StatusDown, want: StatusUp, wantFails: 2},
+		{raw: StatusDown, want: StatusDown, wantFails: 3}, // threshold reached
+		{raw: StatusUp, want: StatusDown, wantOKs: 1},
+		{raw: StatusUp, want: StatusUp, wantOKs: 2},
+	}
+
+	for i, st := range steps {
+		c.storeResult(svc, Result{ServiceName: svc.Name, Status: st.raw, CheckedAt: time.Now()})
+
+		got := c.Snapshot()[svc.Name]
+		if got.Status != st.want || got.RawStatus != st.raw {
+			t.Fatalf("step %d: status=%q raw=%q, want %q raw %q", i, got.Status, got.RawStatus, st.want, st.raw)
+		}
+		if got.ConsecutiveFailures != st.wantFails || got.ConsecutiveSuccesses != st.wantOKs {
+			t.Fatalf("step %d: fails=%d oks=%d, want %d/%d",
+				i, got.ConsecutiveFailures, got.ConsecutiveSuccesses, st.wantFails, st.wantOKs)
+		}
+	}
+}
+
+func TestCheckerNoThresholdFlipsImmediately(t *testing.T) {
+	svc := models.Service{Name: "Plex"}
+	c := NewChecker([]models.Service{svc}, 30*time.Second, 3*time.Second, 2*time.Second)
+
+	c.storeResult(svc, Result{ServiceName: svc.Name, Status: StatusUp})
+	c.storeResult(svc, Result{ServiceName: svc.Name, Status: StatusDown})
+
+	if got := c.Snapshot()[svc.Name].Status; got != StatusDown {
+		t.Fatalf("status=%q, want %q", got, StatusDown)
+	}
+}
+
+// blockingBackend blocks until the check's context is cancelled.
+type blockingBackend struct {
+	started chan struct{}
+}
+
+func (b blockingBackend) Check(ctx context.Context, svc models.Service) Result {
+	b.started <- struct{}{}
+	<-ctx.Done()
+	return Result{ServiceName: svc.Name, Status: StatusDown, Error: ctx.Err().Error()}
+}
+
+func TestCheckerStopCancelsInFlightChecks(t *testing.T) {
+	services := []models.Service{{Name: "Slow", Type: "tcp"}}
+	c := NewChecker(services, time.Hour, time.Hour, time.Hour, WithJitter(0))
+
+	bb := blockingBackend{started: make(chan struct{}, 1)}
+	c.backends["tcp"] = bb
+
+	c.Start()
+	<-bb.started
+
+	stopped := make(chan struct{})
+	go func() {
+		c.Stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Stop did not return after cancelling the in-flight check")
+	}
+
+	if _, ok := c.Snapshot()["Slow"]; ok {
+		t.Fatalf("cancelled check should not be stored as a result")
+	}
+
+	// Stopping twice is harmless.
+	c.Stop()
+}
+
+func TestCheckerRunReturnsOnCancel(t *testing.T) {
+	c := NewChecker([]models.Service{{Name: "Plex"}}, time.Hour, time.Second, time.Second, WithJitter(0))
+	c.backends["http"] = stubBackend{res: Result{Status: StatusUp}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		c.Run(ctx)
+		close(done)
+	}()
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Run did not return after ctx was cancelled")
+	}
+}
@@ -0,0 +1,276 @@
+package health
+
+import (
+	"context"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/models"
+)
+
+// depGraph is the dependency DAG built from models.Service.DependsOn.
+// Unknown names are ignored and edges that would close a cycle are
+// dropped, so walking it always terminates.
+type depGraph struct {
+	parents  map[string][]string // what a service depends on
+	children map[string][]string // what depends on a service
+}
+
+// newDepGraph builds the graph of services, logging dropped cycles.
+func newDepGraph(services []models.Service) depGraph {
+	parents := walkDeps(services, func(cycle []string) {
+		log.Printf("warning: ignoring dependency cycle %s", strings.Join(cycle, " -> "))
+	})
+
+	g := depGraph{parents: parents, children: make(map[string][]string)}
+	for _, svc := range services {
+		for _, p := range parents[svc.Name] {
+			g.children[p] = append(g.children[p], svc.Name)
+		}
+	}
+	return g
+}
+
+// DependencyCycles returns the depends_on cycles among services, each
+// as a path such as [A B A]; none when the dependencies form a DAG.
+// A service depending on itself is a cycle of one, [A A].
+func DependencyCycles(services []models.Service) [][]string {
+	var cycles [][]string
+	walkDeps(services, func(cycle []string) {
+		cycles = append(cycles, cycle)
+	})
+	return cycles
+}
+
+// walkDeps walks the dependencies of services depth first, in config
+// order, and returns the edges that do not close a cycle. onCycle is
+// called with the path of every cycle found.
+func walkDeps(services []models.Service, onCycle func(cycle []string)) map[string][]string {
+	deps := make(map[string][]string, len(services))
+	for _, svc := range services {
+		deps[svc.Name] = svc.DependsOn
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int, len(services))
+	kept := make(map[string][]string, len(services))
+	var stack []string
+
+	var visit func(name string)
+	visit = func(name string) {
+		state[name] = visiting
+		stack = append(stack, name)
+		for _, dep := range deps[name] {
+			if _, ok := deps[dep]; !ok {
+				continue
+			}
+			switch state[dep] {
+			case visiting:
+				i := slices.Index(stack, dep)
+				onCycle(append(slices.Clone(stack[i:]), dep))
+				continue
+			case unvisited:
+				visit(dep)
+			}
+			kept[name] = append(kept[name], dep)
+		}
+		stack = stack[:len(stack)-1]
+		state[name] = done
+	}
+	for _, svc := range services {
+		if state[svc.Name] == unvisited {
+			visit(svc.Name)
+		}
+	}
+	return kept
+}
+
+// dependents returns everything that depends on name, directly or
+// not, nearest first.
+func (g depGraph) dependents(name string) []string {
+	var out []string
+	seen := map[string]bool{name: true}
+	queue := []string{name}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, child := range g.children[cur] {
+			if !seen[child] {
+				seen[child] = true
+				out = append(out, child)
+				queue = append(queue, child)
+			}
+		}
+	}
+	return out
+}
+
+// failedLocked reports whether name is failing in a way that explains
+// failures of what depends on it: DOWN, or failing checks while in
+// maintenance (e.g. a host being rebooted).
+func (c *Checker) failedLocked(name string) bool {
+	res, ok := c.results[name]
+	if !ok {
+		return false
+	}
+	return res.Status == StatusDown ||
+		(res.Status == StatusMaintenance && res.RawStatus != "" && !res.RawStatus.IsUp())
+}
+
+// rootCauseLocked returns the root-most failed service that name
+// depends on, directly or not, or "" if none has failed.
+func (c *Checker) rootCauseLocked(name string) string {
+	root := ""
+	for cur := name; ; {
+		next := c.failedAncestorLocked(cur)
+		if next == "" {
+			return root
+		}
+		root, cur = next, next
+	}
+}
+
+// failedAncestorLocked returns the nearest failed service that name
+// depends on, or "".
+func (c *Checker) failedAncestorLocked(name string) string {
+	seen := map[string]bool{name: true}
+	queue := []string{name}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, p := range c.deps.parents[cur] {
+			if seen[p] {
+				continue
+			}
+			if c.failedLocked(p) {
+				return p
+			}
+			seen[p] = true
+			queue = append(queue, p)
+		}
+	}
+	return ""
+}
+
+// applyDependenciesLocked turns a DOWN result into UNREACHABLE when
+// something svc depends on has failed, attributing it to the root cause.
+func (c *Checker) applyDependenciesLocked(svc models.Service, res Result) Result {
+	if res.Status != StatusDown {
+		return res
+	}
+	if root := c.rootCauseLocked(svc.Name); root != "" {
+		res.Status = StatusUnreachable
+		res.RootCause = root
+	}
+	return res
+}
+
+// affectedLocked lists everything that depends on name, sorted, for
+// the Event of name going DOWN.
+func (c *Checker) affectedLocked(name string) []string {
+	out := c.deps.dependents(name)
+	sort.Strings(out)
+	return out
+}
+
+// verifyDependencies checks the services svc depends on that are
+// still UP before svc is declared DOWN, so an outage upstream shows up
+// as the root cause rather than as a failure of each dependent. Parents
+// whose check is already queued or running are not checked twice; if
+// they turn out DOWN, refreshDependents re-attributes svc.
+func (c *Checker) verifyDependencies(ctx context.Context, svc models.Service) {
+	c.mu.RLock()
+	if !c.results[svc.Name].Status.IsUp() {
+		c.mu.RUnlock()
+		return // already failing; nothing new to attribute
+	}
+	var parents []models.Service
+	for _, p := range c.deps.parents[svc.Name] {
+		res, ok := c.results[p]
+		if !ok || !res.Status.IsUp() {
+			continue
+		}
+		if ps, ok := c.lookupLocked(p); ok {
+			parents = append(parents, ps)
+		}
+	}
+	c.mu.RUnlock()
+
+	for _, p := range parents {
+		if !c.claim(p.Name) {
+			continue
+		}
+		c.checkOne(ctx, p)
+		c.finish(p.Name)
+	}
+}
+
+// refreshDependents re-attributes the dependents of name after its
+// status changed: failing dependents become UNREACHABLE when name (or
+// something above it) failed, and UNREACHABLE ones whose root cause
+// recovered are checked again right away, in the background while Run
+// is active and before returning (bounded by ctx) otherwise.
+func (c *Checker) refreshDependents(ctx context.Context, name string) {
+	type change struct {
+		res Result
+		ev  *Event
+	}
+	var changes []change
+	var recheck []models.Service
+	now := time.Now()
+
+	c.mu.Lock()
+	runCtx, runWG := c.runCtx, c.runWG
+	for _, d := range c.deps.dependents(name) {
+		svc, ok := c.lookupLocked(d)
+		if !ok {
+			continue
+		}
+		res, ok := c.results[d]
+		if !ok || (res.Status != StatusDown && res.Status != StatusUnreachable) {
+			continue
+		}
+
+		root := c.rootCauseLocked(d)
+		switch {
+		case root != "" && (res.Status != StatusUnreachable || res.RootCause != root):
+			prev := res.Status
+			res.Status = StatusUnreachable
+			res.RootCause = root
+			c.results[d] = res
+
+			var ev *Event
+			if prev != StatusUnreachable {
+				ev = c.transitionLocked(svc, prev, res, now)
+			}
+			changes = append(changes, change{res, ev})
+		case root == "" && res.Status == StatusUnreachable:
+			if runWG != nil {
+				runWG.Add(1)
+			}
+			recheck = append(recheck, svc)
+		}
+	}
+	c.mu.Unlock()
+
+	for _, ch := range changes {
+		c.record(ch.res, ch.ev)
+	}
+	for _, svc := range recheck {
+		if runWG == nil {
+			c.checkOne(ctx, svc)
+			continue
+		}
+		go func() {
+			defer runWG.Done()
+			c.checkOne(runCtx, svc)
+		}()
+	}
+}
@@ -0,0 +1,241 @@
+package health
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/models"
+)
+
+// switchBackend reports the services in down as DOWN, the rest as UP,
+// and counts the checks of each service.
+type switchBackend struct {
+	mu     sync.Mutex
+	down   map[string]bool
+	checks map[string]int
+}
+
+func (b *switchBackend) Check(_ context.Context, svc models.Service) Result {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.checks == nil {
+		b.checks = make(map[string]int)
+	}
+	b.checks[svc.Name]++
+	r := Result{ServiceName: svc.Name, CheckedAt: time.Now(), Status: StatusUp}
+	if b.down[svc.Name] {
+		r.Status = StatusDown
+		r.Error = "connect: connection refused"
+	}
+	return r
+}
+
+func (b *switchBackend) count(name string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.checks[name]
+}
+
+func (b *switchBackend) set(down ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.down = make(map[string]bool)
+	for _, name := range down {
+		b.down[name] = true
+	}
+}
+
+func TestDependencyCycles(t *testing.T) {
+	services := []models.Service{
+		{Name: "A", DependsOn: []string{"B"}},
+		{Name: "B", DependsOn: []string{"C", "Ghost"}},
+		{Name: "C", DependsOn: []string{"A"}},
+		{Name: "D", DependsOn: []string{"D"}},
+		{Name: "E", DependsOn: []string{"A"}},
+	}
+	got := DependencyCycles(services)
+	want := [][]string{{"A", "B", "C", "A"}, {"D", "D"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("cycles = %v, want %v", got, want)
+	}
+
+	if got := DependencyCycles(services[4:]); got != nil {
+		t.Fatalf("cycles without any = %v", got)
+	}
+
+	// The checker's graph drops the edge closing each cycle.
+	g := newDepGraph(services)
+	if deps := g.dependents("A"); !reflect.DeepEqual(deps, []string{"E"}) {
+		t.Fatalf("dependents(A) = %v, want [E] (C -> A closes a cycle)", deps)
+	}
+}
+
+func TestCheckerAttributesRootCause(t *testing.T) {
+	services := []models.Service{
+		{Name: "Proxmox", Type: "tcp"},
+		{Name: "NAS", Type: "tcp", DependsOn: []string{"Proxmox"}},
+		{Name: "Plex", Type: "tcp", DependsOn: []string{"NAS"}},
+	}
+	c := NewChecker(services, time.Hour, time.Second, time.Second)
+	b := &switchBackend{}
+	c.backends["tcp"] = b
+	for _, svc := range services {
+		c.CheckNow(context.Background(), svc.Name)
+	}
+
+	events := c.Subscribe()
+	defer c.Unsubscribe(events)
+
+	// Plex fails first, but its dependencies are checked before it is
+	// declared DOWN: the outage belongs to Proxmox.
+	b.set("Proxmox", "NAS", "Plex")
+	c.CheckNow(context.Background(), "Plex")
+
+	ev := recvEvent(t, events)
+	if ev.Service.Name != "Proxmox" || ev.New != StatusDown || !reflect.DeepEqual(ev.Affected, []string{"NAS", "Plex"}) {
+		t.Fatalf("first event = %s %s -> %s affecting %v, want Proxmox DOWN affecting [NAS Plex]",
+			ev.Service.Name, ev.Old, ev.New, ev.Affected)
+	}
+	for _, name := range []string{"NAS", "Plex"} {
+		ev := recvEvent(t, events)
+		if ev.Service.Name != name || ev.New != StatusUnreachable || ev.RootCause != "Proxmox" {
+			t.Fatalf("event = %s %s -> %s (root %q), want %s UNREACHABLE (root Proxmox)",
+				ev.Service.Name, ev.Old, ev.New, ev.RootCause, name)
+		}
+	}
+
+	snap := c.Snapshot()
+	if res := snap["Plex"]; res.Status != StatusUnreachable || res.RawStatus != StatusDown || res.RootCause != "Proxmox" {
+		t.Fatalf("Plex = %s (raw %s, root %q)", res.Status, res.RawStatus, res.RootCause)
+	}
+
+	// Proxmox is back but the NAS is not: the NAS is now the root cause.
+	b.set("NAS", "Plex")
+	c.CheckNow(context.Background(), "Proxmox")
+	c.CheckNow(context.Background(), "NAS")
+	if res := c.Snapshot()["NAS"]; res.Status != StatusDown || res.RootCause != "" {
+		t.Fatalf("NAS = %s (root %q), want DOWN on its own", res.Status, res.RootCause)
+	}
+	if res := c.Snapshot()["Plex"]; res.Status != StatusUnreachable || res.RootCause != "NAS" {
+		t.Fatalf("Plex = %s (root %q), want UNREACHABLE (root NAS)", res.Status, res.RootCause)
+	}
+}
+
+func TestCheckerReattributesWhenDependencyFails(t *testing.T) {
+	services := []models.Service{
+		{Name: "NAS", Type: "tcp"},
+		{Name: "Plex", Type: "tcp", DependsOn: []string{"NAS"}},
+	}
+	c := NewChecker(services, time.Hour, time.Second, time.Second)
+	b := &switchBackend{}
+	c.backends["tcp"] = b
+	c.CheckNow(context.Background(), "NAS")
+
+	// Plex fails on its own while the NAS is fine.
+	b.set("Plex")
+	c.CheckNow(context.Background(), "Plex")
+	if res := c.Snapshot()["Plex"]; res.Status != StatusDown {
+		t.Fatalf("Plex = %s, want DOWN", res.Status)
+	}
+
+	events := c.Subscribe()
+	defer c.Unsubscribe(events)
+
+	b.set("NAS", "Plex")
+	c.CheckNow(context.Background(), "NAS")
+	recvEvent(t, events) // NAS UP -> DOWN
+	ev := recvEvent(t, events)
+	if ev.Service.Name != "Plex" || ev.Old != StatusDown || ev.New != StatusUnreachable || ev.RootCause != "NAS" {
+		t.Fatalf("event = %s %s -> %s (root %q), want Plex DOWN -> UNREACHABLE (root NAS)",
+			ev.Service.Name, ev.Old, ev.New, ev.RootCause)
+	}
+}
+
+func TestCheckerRechecksDependentsOnRecovery(t *testing.T) {
+	services := []models.Service{
+		{Name: "NAS", Type: "tcp"},
+		{Name: "Plex", Type: "tcp", DependsOn: []string{"NAS"}},
+	}
+	c := NewChecker(services, time.Hour, time.Second, time.Second, WithJitter(0))
+	b := &switchBackend{}
+	b.set("NAS", "Plex")
+	c.backends["tcp"] = b
+
+	c.Start()
+	defer c.Stop()
+	waitFor(t, func() bool { return c.Snapshot()["Plex"].Status == StatusUnreachable })
+
+	// The next checks are an hour away; the NAS recovering checks Plex.
+	b.set()
+	c.CheckNow(context.Background(), "NAS")
+	waitFor(t, func() bool { return c.Snapshot()["Plex"].Status == StatusUp })
+}
+
+func TestCheckerHoldsUnreachableUntilRecovered(t *testing.T) {
+	services := []models.Service{
+		{Name: "NAS", Type: "tcp"},
+		{Name: "Plex", Type: "tcp", DependsOn: []string{"NAS"}, SuccessThreshold: 2},
+	}
+	c := NewChecker(services, time.Hour, time.Second, time.Second)
+	b := &switchBackend{}
+	c.backends["tcp"] = b
+	b.set("NAS", "Plex")
+	c.CheckNow(context.Background(), "NAS")
+	c.CheckNow(context.Background(), "Plex")
+	if res := c.Snapshot()["Plex"]; res.Status != StatusUnreachable {
+		t.Fatalf("Plex = %s, want UNREACHABLE", res.Status)
+	}
+
+	events := c.Subscribe()
+	defer c.Unsubscribe(events)
+
+	// Without Run, the NAS recovering checks Plex before returning. One
+	// passing check is not enough for Plex, but it must not show DOWN.
+	b.set()
+	c.CheckNow(context.Background(), "NAS")
+	if n := b.count("Plex"); n != 2 {
+		t.Fatalf("Plex checked %d times, want a recheck after the NAS recovered", n)
+	}
+	if res := c.Snapshot()["Plex"]; res.Status != StatusUnreachable || res.RootCause != "NAS" {
+		t.Fatalf("Plex = %s (root %q), want UNREACHABLE (root NAS) until it passes twice", res.Status, res.RootCause)
+	}
+
+	c.CheckNow(context.Background(), "Plex")
+	for {
+		ev := recvEvent(t, events)
+		if ev.Service.Name != "Plex" {
+			continue
+		}
+		if ev.Old != StatusUnreachable || ev.New != StatusUp {
+			t.Fatalf("Plex event = %s -> %s, want UNREACHABLE -> UP", ev.Old, ev.New)
+		}
+		break
+	}
+}
+
+func TestCheckerSkipsDependencyCheckInFlight(t *testing.T) {
+	services := []models.Service{
+		{Name: "NAS", Type: "tcp"},
+		{Name: "Plex", Type: "tcp", DependsOn: []string{"NAS"}},
+	}
+	c := NewChecker(services, time.Hour, time.Second, time.Second)
+	b := &switchBackend{}
+	c.backends["tcp"] = b
+	c.CheckNow(context.Background(), "NAS")
+	c.CheckNow(context.Background(), "Plex")
+
+	// A scheduled check of the NAS is queued: Plex failing does not
+	// check it a second time.
+	if !c.claim("NAS") {
+		t.Fatal("NAS already in flight")
+	}
+	b.set("NAS", "Plex")
+	c.CheckNow(context.Background(), "Plex")
+	if n := b.count("NAS"); n != 1 {
+		t.Fatalf("NAS checked %d times, want 1", n)
+	}
+}
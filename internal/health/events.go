@@ -0,0 +1,57 @@
+package health
+
+import (
+	"time"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/models"
+)
+
+// Event is published when a service's (debounced) status changes.
+type Event struct {
+	Service models.Service
+	Old     Status
+	New     Status
+
+	// Reason classifies Error, the (redacted) error of the check that
+	// caused the change; both are empty for recoveries.
+	Reason ReasonClass
+	Error  string
+
+	At time.Time // CheckedAt of that check
+
+	// Duration is how long the service was in Old, or zero when that
+	// is not known (no earlier transition on record).
+	Duration time.Duration
+
+	// RootCause is the failed service an UNREACHABLE service depends
+	// on; Affected lists the services that depend on one going DOWN.
+	RootCause string
+	Affected  []string
+}
+
+// Transition returns the Transition recorded for e.
+func (e Event) Transition() Transition {
+	return Transition{
+		ServiceName: e.Service.Name,
+		From:        e.Old,
+		To:          e.New,
+		At:          e.At,
+	}
+}
+
+// Subscribe returns a channel that receives an Event for every status
+// change, including the first result of each service (Old is UNKNOWN).
+// Fan-out never blocks the checks: events are dropped for a subscriber
+// that falls behind by more than a small buffer. Release the channel
+// with Unsubscribe.
+//
+// Results that change no status are not Events; views that show every
+// result subscribe with SubscribeUpdates instead.
+func (c *Checker) Subscribe() <-chan Event {
+	return c.events.subscribe()
+}
+
+// Unsubscribe stops and closes a channel from Subscribe.
+func (c *Checker) Unsubscribe(ch <-chan Event) {
+	c.events.unsubscribe(ch)
+}
@@ -0,0 +1,72 @@
+package health
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/models"
+)
+
+func TestCheckerPublishesEvents(t *testing.T) {
+	svc := models.Service{Name: "NAS", Type: "tcp", Category: "Storage"}
+	c := NewChecker([]models.Service{svc}, time.Hour, time.Second, time.Second)
+	events := c.Subscribe()
+	defer c.Unsubscribe(events)
+
+	up := stubBackend{res: Result{Status: StatusUp}}
+	down := stubBackend{res: Result{Status: StatusDown, Error: "dial tcp: i/o timeout"}}
+
+	c.backends["tcp"] = up
+	c.CheckNow(context.Background(), "NAS")
+	first := recvEvent(t, events)
+	if first.Old != StatusUnknown || first.New != StatusUp || first.Duration != 0 {
+		t.Fatalf("first event = %+v, want UNKNOWN -> UP without a duration", first)
+	}
+
+	// No change, no event.
+	c.CheckNow(context.Background(), "NAS")
+
+	c.backends["tcp"] = down
+	c.CheckNow(context.Background(), "NAS")
+	ev := recvEvent(t, events)
+	if ev.Service.Name != "NAS" || ev.Service.Category != "Storage" {
+		t.Fatalf("event service = %+v", ev.Service)
+	}
+	if ev.Old != StatusUp || ev.New != StatusDown {
+		t.Fatalf("event = %s -> %s, want UP -> DOWN", ev.Old, ev.New)
+	}
+	if ev.Reason != ReasonTimeout || ev.Error != "dial tcp: i/o timeout" {
+		t.Fatalf("reason = %q (%q), want TIMEOUT", ev.Reason, ev.Error)
+	}
+	if want := ev.At.Sub(first.At); ev.Duration != want || want <= 0 {
+		t.Fatalf("duration = %s, want %s (time spent UP)", ev.Duration, want)
+	}
+
+	c.backends["tcp"] = up
+	c.CheckNow(context.Background(), "NAS")
+	if ev := recvEvent(t, events); ev.New != StatusUp || ev.Reason != ReasonNone || ev.Error != "" {
+		t.Fatalf("recovery event = %+v, want UP without a reason", ev)
+	}
+
+	select {
+	case ev := <-events:
+		t.Fatalf("unexpected event %+v", ev)
+	default:
+	}
+	if n := len(c.Transitions("NAS", time.Time{})); n != 3 {
+		t.Fatalf("recorded %d transitions, want 3", n)
+	}
+}
+
+func recvEvent(t *testing.T, ch <-chan Event) Event {
+	t.Helper()
+
+	select {
+	case ev := <-ch:
+		return ev
+	case <-time.After(2 * time.Second):
+		t.Fatalf("no event within 2s")
+		return Event{}
+	}
+}
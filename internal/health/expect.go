@@ -0,0 +1,148 @@
+package health
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/models"
+)
+
+// defaultMaxBodyBytes is used when HTTPExpect.MaxBodyBytes is not set.
+const defaultMaxBodyBytes int64 = 1 << 20
+
+// statusAllowed reports whether code satisfies the expected status list.
+// With no list configured, any 2xx/3xx is accepted.
+func statusAllowed(exp models.HTTPExpect, code int) bool {
+	if len(exp.Status) == 0 {
+		return code >= 200 && code < 400
+	}
+	for _, s := range exp.Status {
+		if s == code {
+			return true
+		}
+	}
+	return false
+}
+
+// regexpCache holds compiled body_regex patterns so a service's pattern
+// is compiled on its first check rather than on every one. It only
+// grows with the patterns in the config, so entries are never evicted.
+type regexpCache struct {
+	m sync.Map // pattern -> *regexp.Regexp
+}
+
+// compile returns the compiled pattern, compiling and caching it on
+// first use. Invalid patterns are not cached.
+func (c *regexpCache) compile(pattern string) (*regexp.Regexp, error) {
+	if re, ok := c.m.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	c.m.Store(pattern, re)
+	return re, nil
+}
+
+// checkBody runs the body assertions and returns a mismatch message,
+// or "" when every assertion passes. BodyRegex is compiled via patterns.
+//
+// Messages are prefixed with "http response:" so ClassifyError maps
+// them to ReasonHTTP.
+func checkBody(exp models.HTTPExpect, body []byte, patterns *regexpCache) string {
+	if exp.BodyContains != "" && !bytes.Contains(body, []byte(exp.BodyContains)) {
+		return fmt.Sprintf("http response: body does not contain %q", exp.BodyContains)
+	}
+
+	if exp.BodyRegex != "" {
+		re, err := patterns.compile(exp.BodyRegex)
+		if err != nil {
+			return fmt.Sprintf("http response: invalid body_regex: %v", err)
+		}
+		if !re.Match(body) {
+			return fmt.Sprintf("http response: body does not match /%s/", exp.BodyRegex)
+		}
+	}
+
+	if exp.JSONPath != "" {
+		var doc any
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.UseNumber()
+		if err := dec.Decode(&doc); err != nil {
+			return fmt.Sprintf("http response: body is not valid JSON: %v", err)
+		}
+
+		val, ok := lookupJSONPath(doc, exp.JSONPath)
+		if !ok {
+			return fmt.Sprintf("http response: json path %q not found", exp.JSONPath)
+		}
+
+		if exp.JSONEquals != "" {
+			got := jsonValueString(val)
+			if got != exp.JSONEquals {
+				return fmt.Sprintf("http response: json path %q is %q, want %q", exp.JSONPath, got, exp.JSONEquals)
+			}
+		}
+	}
+
+	return ""
+}
+
+// lookupJSONPath walks a decoded JSON document using dot notation.
+// Array elements are addressed by index ("items.0.name"). A leading
+// "$." is accepted for familiarity and ignored.
+func lookupJSONPath(doc any, path string) (any, bool) {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	if path == "" {
+		return doc, true
+	}
+
+	cur := doc
+	for _, part := range strings.Split(path, ".") {
+		switch node := cur.(type) {
+		case map[string]any:
+			v, ok := node[part]
+			if !ok {
+				return nil, false
+			}
+			cur = v
+		case []any:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			cur = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// jsonValueString renders a JSON value for comparison with JSONEquals.
+// Scalars are rendered plainly ("ok", "42", "true", "null");
+// objects and arrays as compact JSON.
+func jsonValueString(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return t
+	case json.Number:
+		return t.String()
+	case bool:
+		return strconv.FormatBool(t)
+	default:
+		b, err := json.Marshal(t)
+		if err != nil {
+			return fmt.Sprint(t)
+		}
+		return string(b)
+	}
+}
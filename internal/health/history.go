@@ -0,0 +1,69 @@
+package health
+
+import "time"
+
+// defaultHistoryDepth is how many results are kept per service when
+// WithHistoryDepth is not used (one hour at a 30s interval).
+const defaultHistoryDepth = 120
+
+// HistoryEntry is one recorded check outcome.
+type HistoryEntry struct {
+	CheckedAt   time.Time
+	Status      Status // debounced status after this check
+	RawStatus   Status // what the check itself returned
+	Latency     time.Duration
+	ReasonClass ReasonClass
+	Error       string
+}
+
+// NewHistoryEntry captures the parts of a stored Result worth keeping.
+func NewHistoryEntry(res Result) HistoryEntry {
+	return HistoryEntry{
+		CheckedAt:   res.CheckedAt,
+		Status:      res.Status,
+		RawStatus:   res.RawStatus,
+		Latency:     res.Latency,
+		ReasonClass: ClassifyError(res.Error),
+		Error:       res.Error,
+	}
+}
+
+// historyRing is a fixed-size ring buffer of HistoryEntry values.
+// It is not safe for concurrent use; Checker guards it with its mutex.
+type historyRing struct {
+	buf   []HistoryEntry
+	start int // index of the oldest entry
+	n     int // number of valid entries
+}
+
+func newHistoryRing(size int) *historyRing {
+	if size < 1 {
+		size = 1
+	}
+	return &historyRing{buf: make([]HistoryEntry, size)}
+}
+
+// push appends e, overwriting the oldest entry once full.
+func (r *historyRing) push(e HistoryEntry) {
+	if r.n < len(r.buf) {
+		r.buf[(r.start+r.n)%len(r.buf)] = e
+		r.n++
+		return
+	}
+	r.buf[r.start] = e
+	r.start = (r.start + 1) % len(r.buf)
+}
+
+// since returns entries checked at or after t, oldest first.
+// A zero t returns everything.
+func (r *historyRing) since(t time.Time) []HistoryEntry {
+	out := make([]HistoryEntry, 0, r.n)
+	for i := 0; i < r.n; i++ {
+		e := r.buf[(r.start+i)%len(r.buf)]
+		if !t.IsZero() && e.CheckedAt.Before(t) {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out
+}
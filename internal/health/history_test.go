@@ -0,0 +1,57 @@
+package health
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/models"
+)
+
+func TestHistoryRingWrapsOldestFirst(t *testing.T) {
+	r := newHistoryRing(3)
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 5; i++ {
+		r.push(HistoryEntry{CheckedAt: base.Add(time.Duration(i) * time.Minute)})
+	}
+
+	got := r.since(time.Time{})
+	if len(got) != 3 {
+		t.Fatalf("len=%d, want 3", len(got))
+	}
+	for i, want := range []int{2, 3, 4} {
+		if !got[i].CheckedAt.Equal(base.Add(time.Duration(want) * time.Minute)) {
+			t.Fatalf("entry %d at %v, want minute %d", i, got[i].CheckedAt, want)
+		}
+	}
+
+	recent := r.since(base.Add(4 * time.Minute))
+	if len(recent) != 1 {
+		t.Fatalf("since(minute 4) returned %d entries, want 1", len(recent))
+	}
+}
+
+func TestCheckerHistoryRecordsEveryCheck(t *testing.T) {
+	svc := models.Service{Name: "Plex"}
+	c := NewChecker([]models.Service{svc}, 30*time.Second, 3*time.Second, 2*time.Second, WithHistoryDepth(2))
+
+	if h := c.History("Plex", time.Time{}); h != nil {
+		t.Fatalf("expected nil history before first check, got %v", h)
+	}
+
+	start := time.Now()
+	c.storeResult(svc, Result{ServiceName: "Plex", Status: StatusUp, CheckedAt: start, Latency: 5 * time.Millisecond})
+	c.storeResult(svc, Result{ServiceName: "Plex", Status: StatusDown, CheckedAt: start.Add(time.Second), Error: "dial tcp: i/o timeout"})
+	c.storeResult(svc, Result{ServiceName: "Plex", Status: StatusDown, CheckedAt: start.Add(2 * time.Second), Error: "connection refused"})
+
+	h := c.History("Plex", time.Time{})
+	if len(h) != 2 {
+		t.Fatalf("len=%d, want depth 2", len(h))
+	}
+	if h[0].ReasonClass != ReasonTimeout || h[1].ReasonClass != ReasonConn {
+		t.Fatalf("reasons=(%q,%q), want (%q,%q)", h[0].ReasonClass, h[1].ReasonClass, ReasonTimeout, ReasonConn)
+	}
+	if h[1].RawStatus != StatusDown {
+		t.Fatalf("RawStatus=%q, want %q", h[1].RawStatus, StatusDown)
+	}
+}
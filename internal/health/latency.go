@@ -0,0 +1,73 @@
+package health
+
+import (
+	"sort"
+	"time"
+)
+
+// defaultLatencyWindow is how many latency samples per service feed
+// the percentiles when WithLatencyWindow is not used.
+const defaultLatencyWindow = 60
+
+// LatencyStats summarizes recent latencies of successful checks.
+type LatencyStats struct {
+	Samples int
+	P50     time.Duration
+	P95     time.Duration
+	P99     time.Duration
+}
+
+// latencyWindow is a sliding window of the most recent latency samples.
+// It is not safe for concurrent use; Checker guards it with its mutex.
+type latencyWindow struct {
+	buf  []time.Duration
+	next int
+	full bool
+}
+
+func newLatencyWindow(size int) *latencyWindow {
+	if size < 1 {
+		size = 1
+	}
+	return &latencyWindow{buf: make([]time.Duration, size)}
+}
+
+func (w *latencyWindow) add(d time.Duration) {
+	w.buf[w.next] = d
+	w.next = (w.next + 1) % len(w.buf)
+	if w.next == 0 {
+		w.full = true
+	}
+}
+
+// stats computes percentiles over the current window using the
+// nearest-rank method.
+func (w *latencyWindow) stats() LatencyStats {
+	n := w.next
+	if w.full {
+		n = len(w.buf)
+	}
+	if n == 0 {
+		return LatencyStats{}
+	}
+
+	sorted := make([]time.Duration, n)
+	copy(sorted, w.buf[:n])
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	return LatencyStats{
+		Samples: n,
+		P50:     percentile(sorted, 50),
+		P95:     percentile(sorted, 95),
+		P99:     percentile(sorted, 99),
+	}
+}
+
+// percentile returns the nearest-rank p-th percentile of sorted samples.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100 // ceil(p/100 * n)
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
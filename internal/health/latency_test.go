@@ -0,0 +1,87 @@
+package health
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/models"
+)
+
+func TestLatencyWindowPercentiles(t *testing.T) {
+	w := newLatencyWindow(100)
+	for i := 1; i <= 100; i++ {
+		w.add(time.Duration(i) * time.Millisecond)
+	}
+
+	st := w.stats()
+	if st.Samples != 100 || st.P50 != 50*time.Millisecond || st.P95 != 95*time.Millisecond || st.P99 != 99*time.Millisecond {
+		t.Fatalf("stats = %+v", st)
+	}
+
+	// Sliding: ten more slow samples push out the fastest ones.
+	for i := 0; i < 10; i++ {
+		w.add(time.Second)
+	}
+	st = w.stats()
+	if st.Samples != 100 || st.P95 != time.Second {
+		t.Fatalf("after slide stats = %+v, want p95 1s", st)
+	}
+}
+
+func TestCheckerDegradedOnLatency(t *testing.T) {
+	svc := models.Service{Name: "Plex", LatencyWarn: 200 * time.Millisecond}
+	c := NewChecker([]models.Service{svc}, time.Hour, time.Second, time.Second)
+
+	c.storeResult(svc, Result{ServiceName: "Plex", Status: StatusUp, Latency: 50 * time.Millisecond})
+	if got := c.Snapshot()["Plex"]; got.Status != StatusUp {
+		t.Fatalf("fast check: status=%q, want UP", got.Status)
+	}
+
+	c.storeResult(svc, Result{ServiceName: "Plex", Status: StatusUp, Latency: 900 * time.Millisecond})
+	got := c.Snapshot()["Plex"]
+	if got.Status != StatusDegraded || got.RawStatus != StatusUp {
+		t.Fatalf("slow check: status=%q raw=%q, want DEGRADED/UP", got.Status, got.RawStatus)
+	}
+	if got.Warning != "latency 900ms exceeds 200ms" {
+		t.Fatalf("warning=%q", got.Warning)
+	}
+
+	if st := c.LatencyStats("Plex"); st.Samples != 2 || st.P99 != 900*time.Millisecond {
+		t.Fatalf("LatencyStats = %+v", st)
+	}
+}
+
+func TestCheckerDegradedOnP95AndBackendWarning(t *testing.T) {
+	svc := models.Service{Name: "NAS", LatencyWarnP95: 100 * time.Millisecond}
+	tlsSvc := models.Service{Name: "TLS"}
+	c := NewChecker([]models.Service{svc, tlsSvc}, time.Hour, time.Second, time.Second, WithLatencyWindow(4))
+
+	for _, ms := range []int{10, 10, 10} {
+		c.storeResult(svc, Result{ServiceName: "NAS", Status: StatusUp, Latency: time.Duration(ms) * time.Millisecond})
+	}
+	if got := c.Snapshot()["NAS"].Status; got != StatusUp {
+		t.Fatalf("status=%q, want UP", got)
+	}
+
+	c.storeResult(svc, Result{ServiceName: "NAS", Status: StatusUp, Latency: 500 * time.Millisecond})
+	if got := c.Snapshot()["NAS"].Status; got != StatusDegraded {
+		t.Fatalf("status=%q, want DEGRADED once p95 crosses the threshold", got)
+	}
+
+	// A backend warning (e.g. certificate expiry) also degrades.
+	c.storeResult(tlsSvc, Result{ServiceName: "TLS", Status: StatusUp, Warning: "certificate expires in 3 days"})
+	if got := c.Snapshot()["TLS"].Status; got != StatusDegraded {
+		t.Fatalf("status=%q, want DEGRADED for backend warning", got)
+	}
+}
+
+func TestDebounceFromDegraded(t *testing.T) {
+	svc := models.Service{Name: "Plex", FailureThreshold: 2, LatencyWarn: time.Millisecond}
+	c := NewChecker([]models.Service{svc}, time.Hour, time.Second, time.Second)
+
+	c.storeResult(svc, Result{ServiceName: "Plex", Status: StatusUp, Latency: time.Second})
+	c.storeResult(svc, Result{ServiceName: "Plex", Status: StatusDown})
+	if got := c.Snapshot()["Plex"].Status; got != StatusUp {
+		t.Fatalf("status=%q, want a single failure from DEGRADED to be suppressed", got)
+	}
+}
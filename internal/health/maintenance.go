@@ -0,0 +1,135 @@
+package health
+
+import (
+	"context"
+	"time"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/models"
+)
+
+// maintenanceEvery is how often the Checker looks for maintenance
+// windows that started or ended between checks.
+const maintenanceEvery = 10 * time.Second
+
+// Maintenance describes the window or silence a service is in.
+type Maintenance struct {
+	Name   string // window name, or silence ID
+	By     string // who silenced it (silences only)
+	Reason string
+	Start  time.Time
+	End    time.Time
+}
+
+// equal reports whether m and o describe the same maintenance.
+func (m Maintenance) equal(o Maintenance) bool {
+	return m.Name == o.Name && m.By == o.By && m.Reason == o.Reason &&
+		m.Start.Equal(o.Start) && m.End.Equal(o.End)
+}
+
+// MaintenanceSchedule reports whether a service is in maintenance.
+// Active must be safe for concurrent use and cheap: it is called for
+// every check result.
+type MaintenanceSchedule interface {
+	Active(svc models.Service, at time.Time) (Maintenance, bool)
+}
+
+// WithMaintenance makes results of services in a maintenance window
+// MAINTENANCE rather than their checked status. Checks keep running
+// (RawStatus and history show what they found), but status changes
+// caused by the maintenance itself are not DOWN events and the time is
+// left out of availability.
+func WithMaintenance(s MaintenanceSchedule) Option {
+	return func(c *Checker) {
+		c.maintenance = s
+	}
+}
+
+// applyMaintenanceLocked switches res to MAINTENANCE when svc is in a
+// maintenance window at the time of the check.
+func (c *Checker) applyMaintenanceLocked(svc models.Service, res Result) Result {
+	if c.maintenance == nil {
+		return res
+	}
+	if m, ok := c.maintenance.Active(svc, res.CheckedAt); ok {
+		res.Status = StatusMaintenance
+		res.Maintenance = &m
+	}
+	return res
+}
+
+// RefreshMaintenance applies maintenance windows that started, changed
+// or ended since the last check, without waiting for the next one:
+// services entering maintenance switch to MAINTENANCE right away, and
+// services leaving it are checked immediately. Call it after adding or
+// removing a silence; while Run is active it also runs periodically.
+func (c *Checker) RefreshMaintenance() {
+	if c.maintenance == nil {
+		return
+	}
+	now := time.Now()
+
+	type change struct {
+		res Result
+		ev  *Event
+	}
+	var changes []change
+	var ended []models.Service
+
+	c.mu.Lock()
+	runCtx, runWG := c.runCtx, c.runWG
+	for _, svc := range c.services {
+		res, ok := c.results[svc.Name]
+		if !ok {
+			continue
+		}
+		m, active := c.maintenance.Active(svc, now)
+		switch {
+		case active && (res.Maintenance == nil || !res.Maintenance.equal(m)):
+			prev := res.Status
+			res.Status = StatusMaintenance
+			res.Maintenance = &m
+			c.results[svc.Name] = res
+
+			var ev *Event
+			if prev != StatusMaintenance {
+				ev = c.transitionLocked(svc, prev, res, now)
+			}
+			changes = append(changes, change{res, ev})
+		case !active && res.Maintenance != nil && runWG != nil:
+			runWG.Add(1)
+			ended = append(ended, svc)
+		}
+	}
+	c.mu.Unlock()
+
+	for _, ch := range changes {
+		c.record(ch.res, ch.ev)
+		if ch.ev != nil {
+			c.refreshDependents(context.Background(), ch.res.ServiceName)
+		}
+	}
+	// The status after maintenance comes from a fresh check, so a
+	// result from the middle of a reboot does not raise an alert.
+	for _, svc := range ended {
+		go func() {
+			defer runWG.Done()
+			c.checkOne(runCtx, svc)
+		}()
+	}
+}
+
+// maintenanceLoop calls RefreshMaintenance every maintenanceEvery until
+// ctx is cancelled.
+func (c *Checker) maintenanceLoop(ctx context.Context) {
+	ticker := time.NewTicker(maintenanceEvery)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.RefreshMaintenance()
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/models"
+)
+
+// fakeSchedule puts the services in its set into maintenance.
+type fakeSchedule struct {
+	mu  sync.Mutex
+	in  map[string]bool
+	win Maintenance
+}
+
+func (f *fakeSchedule) Active(svc models.Service, _ time.Time) (Maintenance, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.win, f.in[svc.Name]
+}
+
+func (f *fakeSchedule) set(name string, on bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.in[name] = on
+}
+
+func TestCheckerMaintenance(t *testing.T) {
+	svc := models.Service{Name: "Proxmox", Type: "tcp"}
+	sched := &fakeSchedule{
+		in:  map[string]bool{},
+		win: Maintenance{Name: "patch night", Reason: "kernel update", End: time.Now().Add(time.Hour)},
+	}
+	c := NewChecker([]models.Service{svc}, time.Hour, time.Second, time.Second, WithMaintenance(sched))
+	events := c.Subscribe()
+	defer c.Unsubscribe(events)
+
+	c.backends["tcp"] = stubBackend{res: Result{Status: StatusUp}}
+	c.CheckNow(context.Background(), "Proxmox")
+	recvEvent(t, events) // UNKNOWN -> UP
+
+	// Entering maintenance switches the status without waiting for a check.
+	sched.set("Proxmox", true)
+	c.RefreshMaintenance()
+	ev := recvEvent(t, events)
+	if ev.Old != StatusUp || ev.New != StatusMaintenance || ev.Reason != ReasonNone {
+		t.Fatalf("event = %+v, want UP -> MAINTENANCE without a reason", ev)
+	}
+
+	// Failing checks during maintenance are kept but change nothing.
+	c.backends["tcp"] = stubBackend{res: Result{Status: StatusDown, Error: "connection refused"}}
+	c.CheckNow(context.Background(), "Proxmox")
+	res := c.Snapshot()["Proxmox"]
+	if res.Status != StatusMaintenance || res.RawStatus != StatusDown {
+		t.Fatalf("status = %s (raw %s), want MAINTENANCE (raw DOWN)", res.Status, res.RawStatus)
+	}
+	if res.Maintenance == nil || res.Maintenance.Name != "patch night" {
+		t.Fatalf("maintenance = %+v, want patch night", res.Maintenance)
+	}
+	select {
+	case ev := <-events:
+		t.Fatalf("unexpected event %+v during maintenance", ev)
+	default:
+	}
+
+	// Once it ends, the next check decides the status again.
+	sched.set("Proxmox", false)
+	c.backends["tcp"] = stubBackend{res: Result{Status: StatusUp}}
+	c.CheckNow(context.Background(), "Proxmox")
+	if ev := recvEvent(t, events); ev.Old != StatusMaintenance || ev.New != StatusUp {
+		t.Fatalf("event = %s -> %s, want MAINTENANCE -> UP", ev.Old, ev.New)
+	}
+	if res := c.Snapshot()["Proxmox"]; res.Maintenance != nil {
+		t.Fatalf("maintenance = %+v after it ended", res.Maintenance)
+	}
+}
+
+func TestRefreshMaintenanceChecksWhenWindowEnds(t *testing.T) {
+	svc := models.Service{Name: "Proxmox", Type: "tcp"}
+	sched := &fakeSchedule{in: map[string]bool{"Proxmox": true}}
+	c := NewChecker([]models.Service{svc}, time.Hour, time.Second, time.Second,
+		WithMaintenance(sched), WithJitter(0))
+	fb := newFlakyBackend(1) // DOWN while rebooting, then UP
+	c.backends["tcp"] = fb
+
+	c.Start()
+	defer c.Stop()
+	waitFor(t, func() bool { return c.Snapshot()["Proxmox"].Status == StatusMaintenance })
+
+	// The next check is an hour away; ending the window checks now.
+	sched.set("Proxmox", false)
+	c.RefreshMaintenance()
+	waitFor(t, func() bool { return c.Snapshot()["Proxmox"].Status == StatusUp })
+}
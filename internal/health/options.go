@@ -0,0 +1,87 @@
+package health
+
+import (
+	"time"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/models"
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/redact"
+)
+
+// Option customizes a Checker created by NewChecker.
+type Option func(*Checker)
+
+// WithHistoryDepth sets how many results are kept per service for
+// History. Values below 1 keep the default.
+func WithHistoryDepth(n int) Option {
+	return func(c *Checker) {
+		if n > 0 {
+			c.historyDepth = n
+		}
+	}
+}
+
+// WithLatencyWindow sets how many recent latency samples per service
+// feed LatencyStats. Values below 1 keep the default.
+func WithLatencyWindow(n int) Option {
+	return func(c *Checker) {
+		if n > 0 {
+			c.latencyWindow = n
+		}
+	}
+}
+
+// WithWorkers bounds how many checks run at the same time.
+// Values below 1 keep the default.
+func WithWorkers(n int) Option {
+	return func(c *Checker) {
+		if n > 0 {
+			c.workers = n
+		}
+	}
+}
+
+// WithJitter spreads checks out by a fraction of each service's
+// interval: every run after the first (which is immediate) varies by
+// up to ±jitter/2*interval. 0 disables jitter.
+func WithJitter(fraction float64) Option {
+	return func(c *Checker) {
+		if fraction >= 0 && fraction <= 1 {
+			c.jitter = fraction
+		}
+	}
+}
+
+// WithStore persists results and transitions to s and restores the
+// last known state from it on Start. Data older than retention is
+// compacted away (default 30 days).
+func WithStore(s Store, retention time.Duration) Option {
+	return func(c *Checker) {
+		c.store = s
+		if retention > 0 {
+			c.retention = retention
+		}
+	}
+}
+
+// WithRedactor masks secrets (API tokens, passwords) in every result's
+// Error, Warning and URL before it is stored or persisted.
+func WithRedactor(r *redact.Redactor) Option {
+	return func(c *Checker) {
+		c.redactor = r
+	}
+}
+
+// Observer is told about every completed check, e.g. to export metrics.
+// res is the backend's final result after retries (before flap
+// suppression) and took is the total time spent including retries.
+// ObserveCheck is called from check goroutines and must not block.
+type Observer interface {
+	ObserveCheck(svc models.Service, res Result, took time.Duration)
+}
+
+// WithObserver registers o to be told about every completed check.
+func WithObserver(o Observer) Option {
+	return func(c *Checker) {
+		c.observer = o
+	}
+}
@@ -0,0 +1,92 @@
+package health
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/models"
+)
+
+func TestCheckerUpdateServices(t *testing.T) {
+	keep := models.Service{Name: "Keep", Type: "tcp", Interval: time.Hour}
+	gone := models.Service{Name: "Gone", Type: "tcp", Interval: time.Hour}
+
+	c := NewChecker([]models.Service{keep, gone}, time.Hour, time.Second, time.Second, WithJitter(0))
+	fb := newFlakyBackend(0)
+	c.backends["tcp"] = fb
+
+	c.Start()
+	defer c.Stop()
+
+	waitFor(t, func() bool { return len(c.Snapshot()) == 2 })
+
+	added := models.Service{Name: "Added", Type: "tcp", Interval: time.Hour}
+	c.UpdateServices([]models.Service{keep, added})
+
+	waitFor(t, func() bool { _, ok := c.Snapshot()["Added"]; return ok })
+
+	snap := c.Snapshot()
+	if _, ok := snap["Gone"]; ok {
+		t.Fatalf("removed service should have its result dropped")
+	}
+	if c.History("Gone", time.Time{}) != nil {
+		t.Fatalf("removed service should have its history dropped")
+	}
+	if n := fb.count("Keep"); n != 1 {
+		t.Fatalf("unchanged service was checked %d times, want it left on its schedule (1)", n)
+	}
+	if len(c.History("Keep", time.Time{})) != 1 {
+		t.Fatalf("unchanged service should keep its history")
+	}
+	if ok := c.CheckNow(context.Background(), "Gone"); ok {
+		t.Fatalf("CheckNow should not find a removed service")
+	}
+
+	// Changing a service reschedules it (immediate check) but keeps history.
+	changed := keep
+	changed.Timeout = 5 * time.Second
+	c.UpdateServices([]models.Service{changed, added})
+
+	waitFor(t, func() bool { return fb.count("Keep") == 2 })
+	if n := len(c.History("Keep", time.Time{})); n != 2 {
+		t.Fatalf("changed service history len=%d, want 2", n)
+	}
+}
+
+func TestCheckerUpdateServicesCancelsRemovedInFlight(t *testing.T) {
+	slow := models.Service{Name: "Slow", Type: "tcp"}
+
+	c := NewChecker([]models.Service{slow}, time.Hour, time.Hour, time.Hour, WithJitter(0))
+	bb := blockingBackend{started: make(chan struct{}, 1)}
+	c.backends["tcp"] = bb
+
+	c.Start()
+	defer c.Stop()
+	<-bb.started
+
+	c.UpdateServices(nil)
+
+	// The worker is freed once the removed check is cancelled.
+	waitFor(t, func() bool {
+		c.mu.RLock()
+		defer c.mu.RUnlock()
+		return len(c.inFlight) == 0
+	})
+	if _, ok := c.Snapshot()["Slow"]; ok {
+		t.Fatalf("cancelled check of a removed service must not be stored")
+	}
+}
+
+// waitFor polls cond for up to two seconds.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("condition not met within 2s")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,168 @@
+package health
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/models"
+)
+
+const (
+	// defaultWorkers bounds concurrent checks when WithWorkers is not used.
+	defaultWorkers = 16
+
+	// defaultJitter is the fraction of the interval used to spread checks.
+	defaultJitter = 0.1
+)
+
+// job is one queued check. ctx is the service's scheduling context, so
+// removing the service cancels its in-flight check too.
+type job struct {
+	ctx context.Context
+	svc models.Service
+}
+
+// startScheduleLocked starts the scheduling loop for svc if Run is
+// active. Caller holds c.mu.
+func (c *Checker) startScheduleLocked(svc models.Service) {
+	if c.runCtx == nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(c.runCtx)
+	c.schedules[svc.Name] = cancel
+
+	jobs, wg := c.jobs, c.runWG
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		c.schedule(ctx, svc, jobs)
+	}()
+}
+
+// schedule queues svc for checking right away and then every
+// IntervalFor(svc) (± jitter) until ctx is cancelled. The jitter makes
+// services sharing an interval drift apart instead of all firing at
+// once; the first check is not delayed so tiles fill in on startup.
+func (c *Checker) schedule(ctx context.Context, svc models.Service, jobs chan<- job) {
+	interval := c.IntervalFor(svc)
+
+	c.enqueue(ctx, svc, jobs)
+
+	timer := time.NewTimer(c.nextDelay(interval))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		c.enqueue(ctx, svc, jobs)
+		timer.Reset(c.nextDelay(interval))
+	}
+}
+
+// enqueue hands svc to the worker pool unless its previous check is
+// still queued or running.
+func (c *Checker) enqueue(ctx context.Context, svc models.Service, jobs chan<- job) {
+	if !c.claim(svc.Name) {
+		return
+	}
+
+	c.queued.Add(1)
+	select {
+	case jobs <- job{ctx: ctx, svc: svc}:
+	case <-ctx.Done():
+		c.queued.Add(-1)
+		c.finish(svc.Name)
+	}
+}
+
+// CheckAll checks every configured service once, at most c.workers at
+// a time, and returns when all checks have finished or ctx is done.
+// It does not require Run and is meant for one-shot use.
+func (c *Checker) CheckAll(ctx context.Context) {
+	services := c.Services()
+	jobs := make(chan job)
+
+	var wg sync.WaitGroup
+	for i := 0; i < min(c.workers, len(services)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				c.checkOne(j.ctx, j.svc)
+			}
+		}()
+	}
+
+	for _, svc := range services {
+		select {
+		case jobs <- job{ctx: ctx, svc: svc}:
+		case <-ctx.Done():
+		}
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// worker runs queued checks until ctx is cancelled.
+func (c *Checker) worker(ctx context.Context, jobs <-chan job) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case j := <-jobs:
+			c.queued.Add(-1)
+			c.checkOne(j.ctx, j.svc)
+			c.finish(j.svc.Name)
+		}
+	}
+}
+
+// claim marks a service in flight, reporting false if it already was.
+func (c *Checker) claim(name string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.inFlight[name] {
+		return false
+	}
+	c.inFlight[name] = true
+	return true
+}
+
+// finish clears the in-flight mark for a service.
+func (c *Checker) finish(name string) {
+	c.mu.Lock()
+	delete(c.inFlight, name)
+	c.mu.Unlock()
+}
+
+// Stats is a point-in-time view of the Checker's own load.
+type Stats struct {
+	Running int // checks in progress (scheduled, CheckNow and CheckAll)
+	Queued  int // scheduled checks waiting for a free worker
+	Workers int // size of the worker pool
+}
+
+// Stats reports how busy the Checker is.
+func (c *Checker) Stats() Stats {
+	return Stats{
+		Running: int(c.running.Load()),
+		Queued:  int(c.queued.Load()),
+		Workers: c.workers,
+	}
+}
+
+// nextDelay returns interval varied by up to ±jitter/2*interval.
+func (c *Checker) nextDelay(interval time.Duration) time.Duration {
+	spread := time.Duration(c.jitter * float64(interval))
+	if spread <= 0 {
+		return interval
+	}
+	return interval - spread/2 + rand.N(spread)
+}
@@ -0,0 +1,175 @@
+package health
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/models"
+)
+
+// concurrencyBackend records the peak number of concurrent checks,
+// overall and per service.
+type concurrencyBackend struct {
+	delay time.Duration
+
+	mu         sync.Mutex
+	running    int
+	peak       int
+	perService map[string]int
+	peakPerSvc int
+	calls      int
+}
+
+func (b *concurrencyBackend) Check(ctx context.Context, svc models.Service) Result {
+	b.mu.Lock()
+	b.running++
+	b.calls++
+	if b.running > b.peak {
+		b.peak = b.running
+	}
+	b.perService[svc.Name]++
+	if b.perService[svc.Name] > b.peakPerSvc {
+		b.peakPerSvc = b.perService[svc.Name]
+	}
+	b.mu.Unlock()
+
+	select {
+	case <-time.After(b.delay):
+	case <-ctx.Done():
+	}
+
+	b.mu.Lock()
+	b.running--
+	b.perService[svc.Name]--
+	b.mu.Unlock()
+
+	return Result{ServiceName: svc.Name, Status: StatusUp, CheckedAt: time.Now()}
+}
+
+func TestSchedulerBoundsConcurrency(t *testing.T) {
+	var services []models.Service
+	for i := 0; i < 20; i++ {
+		services = append(services, models.Service{Name: "svc-" + strconv.Itoa(i), Type: "tcp"})
+	}
+
+	c := NewChecker(services, time.Hour, time.Second, time.Second, WithWorkers(3), WithJitter(0))
+	b := &concurrencyBackend{delay: 20 * time.Millisecond, perService: make(map[string]int)}
+	c.backends["tcp"] = b
+
+	c.Start()
+	deadline := time.Now().Add(2 * time.Second)
+	for len(c.Snapshot()) < len(services) && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	c.Stop()
+
+	if n := len(c.Snapshot()); n != len(services) {
+		t.Fatalf("checked %d services, want %d", n, len(services))
+	}
+	if b.peak > 3 {
+		t.Fatalf("peak concurrency %d, want at most 3 workers", b.peak)
+	}
+}
+
+func TestCheckAllRunsOnePassOnTheWorkerPool(t *testing.T) {
+	var services []models.Service
+	for i := 0; i < 10; i++ {
+		services = append(services, models.Service{Name: "svc-" + strconv.Itoa(i), Type: "tcp"})
+	}
+
+	c := NewChecker(services, time.Hour, time.Second, time.Second, WithWorkers(2))
+	b := &concurrencyBackend{delay: 5 * time.Millisecond, perService: make(map[string]int)}
+	c.backends["tcp"] = b
+
+	c.CheckAll(context.Background())
+
+	if n := len(c.Snapshot()); n != len(services) {
+		t.Fatalf("checked %d services, want %d", n, len(services))
+	}
+	if b.calls != len(services) || b.peak > 2 {
+		t.Fatalf("calls=%d peak=%d, want %d calls on at most 2 workers", b.calls, b.peak, len(services))
+	}
+}
+
+func TestCheckerStatsCountsRunningAndQueued(t *testing.T) {
+	services := []models.Service{
+		{Name: "a", Type: "tcp"},
+		{Name: "b", Type: "tcp"},
+		{Name: "c", Type: "tcp"},
+	}
+	c := NewChecker(services, time.Hour, time.Hour, time.Hour, WithWorkers(1), WithJitter(0))
+	bb := blockingBackend{started: make(chan struct{}, len(services))}
+	c.backends["tcp"] = bb
+
+	c.Start()
+	<-bb.started
+
+	// One worker is stuck in a check; the other two services wait.
+	deadline := time.Now().Add(2 * time.Second)
+	for c.Stats().Queued != 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if st := c.Stats(); st.Running != 1 || st.Queued != 2 || st.Workers != 1 {
+		t.Fatalf("Stats = %+v, want 1 running, 2 queued, 1 worker", st)
+	}
+
+	c.Stop()
+	if st := c.Stats(); st.Running != 0 || st.Queued != 0 {
+		t.Fatalf("after Stop: Stats = %+v", st)
+	}
+}
+
+func TestSchedulerSkipsOverlappingRuns(t *testing.T) {
+	// Interval much shorter than the check itself.
+	services := []models.Service{{Name: "Slow", Type: "tcp", Interval: 5 * time.Millisecond}}
+
+	c := NewChecker(services, time.Hour, time.Second, time.Second, WithJitter(0))
+	b := &concurrencyBackend{delay: 50 * time.Millisecond, perService: make(map[string]int)}
+	c.backends["tcp"] = b
+
+	c.Start()
+	time.Sleep(160 * time.Millisecond)
+	c.Stop()
+
+	if b.peakPerSvc != 1 {
+		t.Fatalf("peak concurrent checks for one service = %d, want 1", b.peakPerSvc)
+	}
+	if b.calls > 4 {
+		t.Fatalf("backend called %d times in 160ms with a 50ms check, overlapping runs were not skipped", b.calls)
+	}
+}
+
+func TestSchedulerChecksImmediatelyDespiteJitter(t *testing.T) {
+	services := []models.Service{{Name: "NAS", Type: "tcp"}}
+	c := NewChecker(services, time.Hour, time.Second, time.Second, WithJitter(1))
+	c.backends["tcp"] = &concurrencyBackend{perService: make(map[string]int)}
+
+	c.Start()
+	defer c.Stop()
+
+	deadline := time.Now().Add(time.Second)
+	for len(c.Snapshot()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("first check was delayed by jitter")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestSchedulerJitterBounds(t *testing.T) {
+	c := NewChecker(nil, time.Minute, time.Second, time.Second, WithJitter(0.2))
+
+	for i := 0; i < 100; i++ {
+		if d := c.nextDelay(time.Minute); d < 54*time.Second || d >= 66*time.Second {
+			t.Fatalf("nextDelay=%v, want [54s, 66s)", d)
+		}
+	}
+
+	none := NewChecker(nil, time.Minute, time.Second, time.Second, WithJitter(0))
+	if d := none.nextDelay(time.Minute); d != time.Minute {
+		t.Fatalf("nextDelay without jitter = %v, want 1m", d)
+	}
+}
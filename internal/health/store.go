@@ -0,0 +1,39 @@
+package health
+
+import "time"
+
+// Transition records a change of a service's (debounced) status.
+type Transition struct {
+	ServiceName string
+	From        Status
+	To          Status
+	At          time.Time
+}
+
+// StoredState is everything a Store hands back on startup.
+type StoredState struct {
+	Results     map[string]Result         // last result per service
+	History     map[string][]HistoryEntry // oldest first
+	Transitions map[string][]Transition   // oldest first
+}
+
+// Store persists results and transitions so the Checker can restore
+// its last known state after a restart. Implementations must be safe
+// for concurrent use.
+type Store interface {
+	// SaveResult records one stored (debounced) check result.
+	SaveResult(res Result) error
+
+	// SaveTransition records a status change.
+	SaveTransition(t Transition) error
+
+	// Load replays everything persisted so far.
+	Load() (*StoredState, error)
+
+	// Compact drops data older than before, keeping at least the
+	// latest result of every service and the transition that defines
+	// its state at before.
+	Compact(before time.Time) error
+
+	Close() error
+}
@@ -0,0 +1,145 @@
+package health
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/models"
+)
+
+// memStore is an in-memory Store for tests.
+type memStore struct {
+	mu          sync.Mutex
+	results     []Result
+	transitions []Transition
+}
+
+func (m *memStore) SaveResult(res Result) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.results = append(m.results, res)
+	return nil
+}
+
+func (m *memStore) SaveTransition(t Transition) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.transitions = append(m.transitions, t)
+	return nil
+}
+
+func (m *memStore) Load() (*StoredState, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	st := &StoredState{
+		Results:     make(map[string]Result),
+		History:     make(map[string][]HistoryEntry),
+		Transitions: make(map[string][]Transition),
+	}
+	for _, r := range m.results {
+		st.Results[r.ServiceName] = r
+		st.History[r.ServiceName] = append(st.History[r.ServiceName], NewHistoryEntry(r))
+	}
+	for _, t := range m.transitions {
+		st.Transitions[t.ServiceName] = append(st.Transitions[t.ServiceName], t)
+	}
+	return st, nil
+}
+
+func (m *memStore) Compact(time.Time) error { return nil }
+func (m *memStore) Close() error            { return nil }
+
+func TestCheckerPersistsAndRestores(t *testing.T) {
+	svc := models.Service{Name: "Plex"}
+	st := &memStore{}
+
+	c1 := NewChecker([]models.Service{svc}, time.Hour, time.Second, time.Second, WithStore(st, 0))
+	now := time.Now()
+	c1.storeResult(svc, Result{ServiceName: "Plex", Status: StatusUp, CheckedAt: now.Add(-time.Minute)})
+	c1.storeResult(svc, Result{ServiceName: "Plex", Status: StatusDown, CheckedAt: now, Error: "i/o timeout"})
+
+	if len(st.results) != 2 || len(st.transitions) != 2 {
+		t.Fatalf("persisted %d results / %d transitions, want 2/2", len(st.results), len(st.transitions))
+	}
+
+	// A fresh checker (as after a restart) restores from the same store.
+	c2 := NewChecker([]models.Service{svc}, time.Hour, time.Second, time.Second, WithStore(st, 0))
+	if err := c2.restore(); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+
+	got := c2.Snapshot()["Plex"]
+	if got.Status != StatusDown || !got.Restored {
+		t.Fatalf("restored result = %+v, want DOWN and Restored", got)
+	}
+	if n := len(c2.History("Plex", time.Time{})); n != 2 {
+		t.Fatalf("restored history len=%d, want 2", n)
+	}
+	// Plus the gap while Aurora was stopped, which is persisted too.
+	ts := c2.Transitions("Plex", time.Time{})
+	if len(ts) != 4 || ts[1].To != StatusDown || ts[2].To != StatusUnknown || ts[3].To != StatusDown {
+		t.Fatalf("restored transitions = %+v, want UP, DOWN, then a gap back to DOWN", ts)
+	}
+	if len(st.transitions) != 4 {
+		t.Fatalf("persisted %d transitions after restore, want 4", len(st.transitions))
+	}
+
+	// The next real check clears the Restored flag.
+	c2.storeResult(svc, Result{ServiceName: "Plex", Status: StatusDown, CheckedAt: time.Now()})
+	if c2.Snapshot()["Plex"].Restored {
+		t.Fatalf("expected Restored to be cleared by a fresh check")
+	}
+}
+
+func TestRestoreLeavesDowntimeGapOutOfAvailability(t *testing.T) {
+	svc := models.Service{Name: "NAS"}
+	now := time.Now()
+	h := func(n int) time.Time { return now.Add(time.Duration(-n) * time.Hour) }
+
+	// DOWN since 20h ago when Aurora stopped 18h ago.
+	st := &memStore{
+		results: []Result{{ServiceName: "NAS", Status: StatusDown, CheckedAt: h(18)}},
+		transitions: []Transition{
+			{ServiceName: "NAS", From: StatusUnknown, To: StatusUp, At: h(30)},
+			{ServiceName: "NAS", From: StatusUp, To: StatusDown, At: h(20)},
+		},
+	}
+	c := NewChecker([]models.Service{svc}, time.Hour, time.Second, time.Second, WithStore(st, 0))
+	if err := c.restore(); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+
+	a := c.Availability("NAS", Window24h)
+	if a.Uptime.Round(time.Minute) != 4*time.Hour || a.Downtime.Round(time.Minute) != 2*time.Hour {
+		t.Fatalf("up/down = %v/%v, want 4h/2h with the 18h gap left out", a.Uptime, a.Downtime)
+	}
+
+	events := c.Subscribe()
+	defer c.Unsubscribe(events)
+	c.storeResult(svc, Result{ServiceName: "NAS", Status: StatusUp, CheckedAt: time.Now()})
+
+	ev := <-events
+	if ev.Old != StatusDown || ev.Duration.Round(time.Hour) != 20*time.Hour {
+		t.Fatalf("recovery = %s after %v, want DOWN for 20h across the gap", ev.Old, ev.Duration)
+	}
+	if a := c.Availability("NAS", Window24h); a.Incidents != 1 || a.Resolved != 1 {
+		t.Fatalf("incidents/resolved = %d/%d, want one outage spanning the gap", a.Incidents, a.Resolved)
+	}
+}
+
+func TestPruneTransitionsKeepsBaseline(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	ts := []Transition{
+		{To: StatusUp, At: base},
+		{To: StatusDown, At: base.Add(1 * time.Hour)},
+		{To: StatusUp, At: base.Add(2 * time.Hour)},
+		{To: StatusDown, At: base.Add(5 * time.Hour)},
+	}
+
+	got := pruneTransitions(ts, base.Add(3*time.Hour))
+	if len(got) != 2 || got[0].To != StatusUp || !got[0].At.Equal(base.Add(2*time.Hour)) {
+		t.Fatalf("pruneTransitions = %+v, want baseline UP at +2h and DOWN at +5h", got)
+	}
+}
@@ -0,0 +1,172 @@
+package health
+
+import "time"
+
+// Standard reporting windows for availability.
+const (
+	Window24h = 24 * time.Hour
+	Window7d  = 7 * 24 * time.Hour
+	Window30d = 30 * 24 * time.Hour
+)
+
+// UptimeWindows lists the windows shown on the dashboard.
+var UptimeWindows = []time.Duration{Window24h, Window7d, Window30d}
+
+// Availability summarizes a service (or a group of services) over a window.
+// DEGRADED counts as up; time in UNKNOWN, STALE or MAINTENANCE state
+// is not counted either way, and neither is UNREACHABLE: that outage
+// and its incident belong to the root cause. Time Aurora itself was
+// not running is UNKNOWN (see Checker.Run).
+type Availability struct {
+	Window time.Duration
+
+	Uptime   time.Duration
+	Downtime time.Duration
+
+	// Incidents counts DOWN periods that started inside the window.
+	Incidents int
+
+	// Resolved counts DOWN periods that ended inside the window, and
+	// RepairTime is their total length (clipped to the window). A
+	// period ends when the service is back UP or DEGRADED; time in
+	// between in any other state (say MAINTENANCE, or UNREACHABLE
+	// while it is being recovered) is still part of the repair.
+	Resolved   int
+	RepairTime time.Duration
+}
+
+// Observed is the time with a known UP/DOWN state.
+func (a Availability) Observed() time.Duration {
+	return a.Uptime + a.Downtime
+}
+
+// HasData reports whether any time in the window has a known state.
+func (a Availability) HasData() bool {
+	return a.Observed() > 0
+}
+
+// Percent returns uptime as a percentage of observed time (0 without data).
+func (a Availability) Percent() float64 {
+	if !a.HasData() {
+		return 0
+	}
+	return 100 * float64(a.Uptime) / float64(a.Observed())
+}
+
+// MTTR is the mean time to recovery of resolved incidents.
+func (a Availability) MTTR() time.Duration {
+	if a.Resolved == 0 {
+		return 0
+	}
+	return a.RepairTime / time.Duration(a.Resolved)
+}
+
+// MTBF is the mean up time between failures.
+func (a Availability) MTBF() time.Duration {
+	if a.Incidents == 0 {
+		return 0
+	}
+	return a.Uptime / time.Duration(a.Incidents)
+}
+
+// CombineAvailability adds several availabilities together, e.g. to
+// roll services up into a category. Window is taken from the first.
+func CombineAvailability(as ...Availability) Availability {
+	var out Availability
+	for i, a := range as {
+		if i == 0 {
+			out.Window = a.Window
+		}
+		out.Uptime += a.Uptime
+		out.Downtime += a.Downtime
+		out.Incidents += a.Incidents
+		out.Resolved += a.Resolved
+		out.RepairTime += a.RepairTime
+	}
+	return out
+}
+
+// countsAsUp/countsAsDown classify a status for availability purposes.
+func countsAsUp(s Status) bool   { return s.IsUp() }
+func countsAsDown(s Status) bool { return s == StatusDown }
+
+// ComputeAvailability derives availability over [now-window, now] from
+// a service's transitions (oldest first). The last transition at or
+// before the window start defines the initial state.
+func ComputeAvailability(ts []Transition, window time.Duration, now time.Time) Availability {
+	a := Availability{Window: window}
+	start := now.Add(-window)
+
+	var downSince time.Time // start of the current DOWN period, clipped
+	inDown := false
+
+	for i, t := range ts {
+		segStart := t.At
+		if segStart.Before(start) {
+			segStart = start
+		}
+		segEnd := now
+		if i+1 < len(ts) {
+			segEnd = ts[i+1].At
+		}
+		if !segEnd.After(start) {
+			continue
+		}
+		if segEnd.After(now) {
+			segEnd = now
+		}
+
+		// A gap that returns to the state before it (a restart) does
+		// not end that state's period.
+		if t.To == StatusUnknown && i+1 < len(ts) && ts[i+1].To == t.From {
+			continue
+		}
+
+		down := countsAsDown(t.To)
+		switch {
+		case countsAsUp(t.To):
+			a.Uptime += segEnd.Sub(segStart)
+		case down:
+			a.Downtime += segEnd.Sub(segStart)
+		}
+
+		if down && !inDown {
+			inDown = true
+			downSince = segStart
+			if t.At.After(start) {
+				a.Incidents++
+			}
+		}
+		if countsAsUp(t.To) && inDown {
+			inDown = false
+			a.Resolved++
+			a.RepairTime += segStart.Sub(downSince)
+		}
+	}
+
+	return a
+}
+
+// Availability computes availability for one service over window.
+func (c *Checker) Availability(name string, window time.Duration) Availability {
+	now := time.Now()
+	return ComputeAvailability(c.Transitions(name, now.Add(-window)), window, now)
+}
+
+// CategoryAvailability rolls availability up by models.Service.Category.
+// Services without a category are grouped under "".
+func (c *Checker) CategoryAvailability(window time.Duration) map[string]Availability {
+	c.mu.RLock()
+	services := c.services
+	c.mu.RUnlock()
+
+	out := make(map[string]Availability)
+	for _, svc := range services {
+		out[svc.Category] = CombineAvailability(out[svc.Category], c.Availability(svc.Name, window))
+	}
+	for cat, a := range out {
+		a.Window = window
+		out[cat] = a
+	}
+	return out
+}
@@ -0,0 +1,114 @@
+package health
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestComputeAvailability(t *testing.T) {
+	now := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	h := func(n int) time.Time { return now.Add(time.Duration(-n) * time.Hour) }
+
+	ts := []Transition{
+		{From: StatusUnknown, To: StatusUp, At: h(30)}, // before the 24h window
+		{From: StatusUp, To: StatusDown, At: h(20)},
+		{From: StatusDown, To: StatusUp, At: h(18)},
+		{From: StatusUp, To: StatusDown, At: h(10)},
+		{From: StatusDown, To: StatusUp, At: h(9)},
+		{From: StatusUp, To: StatusDown, At: h(1)}, // still down
+	}
+
+	a := ComputeAvailability(ts, Window24h, now)
+
+	if a.Downtime != 4*time.Hour {
+		t.Fatalf("Downtime=%v, want 4h", a.Downtime)
+	}
+	if a.Uptime != 20*time.Hour {
+		t.Fatalf("Uptime=%v, want 20h", a.Uptime)
+	}
+	if a.Incidents != 3 || a.Resolved != 2 {
+		t.Fatalf("Incidents=%d Resolved=%d, want 3/2", a.Incidents, a.Resolved)
+	}
+	if a.MTTR() != 90*time.Minute {
+		t.Fatalf("MTTR=%v, want 1h30m", a.MTTR())
+	}
+	if got, want := a.Percent(), 100*20.0/24.0; math.Abs(got-want) > 1e-9 {
+		t.Fatalf("Percent=%v, want %v", got, want)
+	}
+}
+
+func TestComputeAvailabilityIgnoresUnknownAndPreWindowState(t *testing.T) {
+	now := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	ts := []Transition{
+		{To: StatusDown, At: now.Add(-48 * time.Hour)}, // DOWN across the window start
+		{To: StatusUnknown, At: now.Add(-12 * time.Hour)},
+		{To: StatusUp, At: now.Add(-6 * time.Hour)},
+	}
+
+	a := ComputeAvailability(ts, Window24h, now)
+
+	if a.Downtime != 12*time.Hour || a.Uptime != 6*time.Hour {
+		t.Fatalf("up/down=%v/%v, want 6h/12h", a.Uptime, a.Downtime)
+	}
+	if a.Incidents != 0 {
+		t.Fatalf("Incidents=%d, want 0 (outage began before the window)", a.Incidents)
+	}
+	// Not known to be repaired until it is seen UP again.
+	if a.Resolved != 1 || a.RepairTime != 18*time.Hour {
+		t.Fatalf("Resolved=%d RepairTime=%v, want 1/18h", a.Resolved, a.RepairTime)
+	}
+}
+
+func TestComputeAvailabilityIncidentSpansMaintenanceAndUnreachable(t *testing.T) {
+	now := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	h := func(n int) time.Time { return now.Add(time.Duration(-n) * time.Hour) }
+
+	ts := []Transition{
+		{From: StatusUnknown, To: StatusUp, At: h(30)},
+		{From: StatusUp, To: StatusDown, At: h(20)},
+		{From: StatusDown, To: StatusMaintenance, At: h(19)},
+		{From: StatusMaintenance, To: StatusUp, At: h(16)},
+		{From: StatusUp, To: StatusDown, At: h(10)},
+		{From: StatusDown, To: StatusUnreachable, At: h(9)},
+		{From: StatusUnreachable, To: StatusDown, At: h(8)},
+		{From: StatusDown, To: StatusDegraded, At: h(6)},
+	}
+
+	a := ComputeAvailability(ts, Window24h, now)
+
+	if a.Downtime != 4*time.Hour || a.Uptime != 16*time.Hour {
+		t.Fatalf("up/down=%v/%v, want 16h/4h", a.Uptime, a.Downtime)
+	}
+	if a.Incidents != 2 || a.Resolved != 2 {
+		t.Fatalf("Incidents=%d Resolved=%d, want 2/2", a.Incidents, a.Resolved)
+	}
+	if a.MTTR() != 4*time.Hour {
+		t.Fatalf("MTTR=%v, want 4h", a.MTTR())
+	}
+	if a.MTBF() != 8*time.Hour {
+		t.Fatalf("MTBF=%v, want 8h", a.MTBF())
+	}
+}
+
+func TestComputeAvailabilityNoData(t *testing.T) {
+	a := ComputeAvailability(nil, Window24h, time.Now())
+	if a.HasData() || a.Percent() != 0 || a.MTTR() != 0 || a.MTBF() != 0 {
+		t.Fatalf("expected empty availability, got %+v", a)
+	}
+}
+
+func TestCombineAvailability(t *testing.T) {
+	a := CombineAvailability(
+		Availability{Window: Window30d, Uptime: 9 * time.Hour, Downtime: time.Hour, Incidents: 1, Resolved: 1, RepairTime: time.Hour},
+		Availability{Window: Window30d, Uptime: 10 * time.Hour},
+	)
+
+	if got := a.Percent(); math.Abs(got-95) > 1e-9 {
+		t.Fatalf("Percent=%v, want 95", got)
+	}
+	if a.MTBF() != 19*time.Hour {
+		t.Fatalf("MTBF=%v, want 19h", a.MTBF())
+	}
+}
@@ -0,0 +1,180 @@
+package maintenance
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cron is a parsed five-field cron expression,
+//
+//	minute hour day-of-month month day-of-week
+//
+// with *, lists (1,15), ranges (1-5), steps (*/15, 0-30/10) and month
+// and weekday names (jan, mon). Day-of-week 0 and 7 are Sunday. As in
+// cron, when both day fields are restricted a day matching either one
+// matches.
+type Cron struct {
+	minute, hour, dom, month, dow uint64 // bit i set: value i matches
+	domAny, dowAny                bool
+}
+
+var (
+	monthNames = map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}
+	dowNames = map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}
+)
+
+// ParseCron parses a five-field cron expression.
+func ParseCron(spec string) (*Cron, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron %q: want 5 fields (minute hour day-of-month month day-of-week), got %d", spec, len(fields))
+	}
+
+	var c Cron
+	var err error
+	parse := func(i int, min, max int, names map[string]int) uint64 {
+		if err != nil {
+			return 0
+		}
+		var bits uint64
+		bits, err = parseField(fields[i], min, max, names)
+		if err != nil {
+			err = fmt.Errorf("cron %q: %w", spec, err)
+		}
+		return bits
+	}
+	c.minute = parse(0, 0, 59, nil)
+	c.hour = parse(1, 0, 23, nil)
+	c.dom = parse(2, 1, 31, nil)
+	c.month = parse(3, 1, 12, monthNames)
+	c.dow = parse(4, 0, 7, dowNames)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1 // 7 is Sunday too
+	}
+	c.domAny = fields[2] == "*"
+	c.dowAny = fields[4] == "*"
+	return &c, nil
+}
+
+// parseField parses one comma-separated field into a bit set.
+func parseField(field string, min, max int, names map[string]int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		expr, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepStr)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("bad step %q", part)
+			}
+			step = n
+		}
+
+		lo, hi := min, max
+		switch {
+		case expr == "*":
+		case strings.Contains(expr, "-"):
+			a, b, _ := strings.Cut(expr, "-")
+			var err error
+			if lo, err = fieldValue(a, min, max, names); err != nil {
+				return 0, err
+			}
+			if hi, err = fieldValue(b, min, max, names); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("bad range %q", expr)
+			}
+		default:
+			v, err := fieldValue(expr, min, max, names)
+			if err != nil {
+				return 0, err
+			}
+			lo = v
+			if !hasStep {
+				hi = v
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+// fieldValue parses a number or name within [min, max].
+func fieldValue(s string, min, max int, names map[string]int) (int, error) {
+	if v, ok := names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < min || v > max {
+		return 0, fmt.Errorf("bad value %q (want %d-%d)", s, min, max)
+	}
+	return v, nil
+}
+
+// matchesDay reports whether the date of t matches the day fields.
+func (c *Cron) matchesDay(t time.Time) bool {
+	if c.month&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+	dom := c.dom&(1<<uint(t.Day())) != 0
+	dow := c.dow&(1<<uint(t.Weekday())) != 0
+	switch {
+	case c.domAny && c.dowAny:
+		return true
+	case c.domAny:
+		return dow
+	case c.dowAny:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+// Prev returns the latest time in (after, t] the expression matches, in
+// t's location, or false if there is none.
+func (c *Cron) Prev(t, after time.Time) (time.Time, bool) {
+	loc := t.Location()
+	after = after.In(loc)
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	first := time.Date(after.Year(), after.Month(), after.Day(), 0, 0, 0, 0, loc)
+
+	for ; !day.Before(first); day = day.AddDate(0, 0, -1) {
+		if !c.matchesDay(day) {
+			continue
+		}
+		for h := 23; h >= 0; h-- {
+			if c.hour&(1<<h) == 0 {
+				continue
+			}
+			for m := 59; m >= 0; m-- {
+				if c.minute&(1<<m) == 0 {
+					continue
+				}
+				at := time.Date(day.Year(), day.Month(), day.Day(), h, m, 0, 0, loc)
+				if at.After(t) {
+					continue
+				}
+				if !at.After(after) {
+					return time.Time{}, false
+				}
+				return at, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,72 @@
+package maintenance
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronErrors(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"0 3 * *",
+		"60 3 * * *",
+		"0 24 * * *",
+		"0 3 0 * *",
+		"0 3 * 13 *",
+		"0 3 * * 8",
+		"0 3 * * funday",
+		"*/0 * * * *",
+		"30-10 * * * *",
+	} {
+		if _, err := ParseCron(spec); err == nil {
+			t.Errorf("ParseCron(%q): want error", spec)
+		}
+	}
+}
+
+func TestCronPrev(t *testing.T) {
+	at := func(s string) time.Time {
+		t.Helper()
+		tm, err := time.ParseInLocation("2006-01-02 15:04", s, time.UTC)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tm
+	}
+
+	tests := []struct {
+		spec     string
+		now      string
+		lookback time.Duration
+		want     string // "" for no match
+	}{
+		// 2026-03-01 is a Sunday.
+		{"0 3 * * sun", "2026-03-01 04:30", 2 * time.Hour, "2026-03-01 03:00"},
+		{"0 3 * * 7", "2026-03-01 04:30", 2 * time.Hour, "2026-03-01 03:00"},
+		{"0 3 * * sun", "2026-03-01 05:30", 2 * time.Hour, ""},
+		{"0 3 * * sun", "2026-03-01 02:59", 2 * time.Hour, ""},
+		{"0 3 * * mon-fri", "2026-03-03 03:10", time.Hour, "2026-03-03 03:00"},
+		{"*/15 * * * *", "2026-03-03 10:44", time.Hour, "2026-03-03 10:30"},
+		{"0 22 * * *", "2026-03-04 01:00", 8 * time.Hour, "2026-03-03 22:00"},
+		{"0 0 1 * *", "2026-03-01 00:00", time.Minute, "2026-03-01 00:00"},
+		// Both day fields restricted: the 15th or any Monday.
+		{"0 12 15 * mon", "2026-03-02 12:05", time.Hour, "2026-03-02 12:00"},
+		{"0 12 15 * mon", "2026-03-15 12:05", time.Hour, "2026-03-15 12:00"},
+		{"0 12 15 * mon", "2026-03-03 12:05", time.Hour, ""},
+		{"0 3 * jun *", "2026-03-01 03:05", time.Hour, ""},
+	}
+	for _, tt := range tests {
+		c, err := ParseCron(tt.spec)
+		if err != nil {
+			t.Fatalf("ParseCron(%q): %v", tt.spec, err)
+		}
+		now := at(tt.now)
+		got, ok := c.Prev(now, now.Add(-tt.lookback))
+		switch {
+		case tt.want == "" && ok:
+			t.Errorf("%q at %s: got %s, want no match", tt.spec, tt.now, got)
+		case tt.want != "" && (!ok || !got.Equal(at(tt.want))):
+			t.Errorf("%q at %s: got %s (%v), want %s", tt.spec, tt.now, got, ok, tt.want)
+		}
+	}
+}
@@ -0,0 +1,300 @@
+// Package maintenance decides when services are in maintenance:
+// scheduled windows from the config and ad-hoc silences created from
+// the dashboard or the API.
+package maintenance
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/health"
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/models"
+)
+
+// Config is the maintenance section of the config file.
+type Config struct {
+	Windows []Window `yaml:"windows,omitempty"`
+
+	// SilencesFile keeps ad-hoc silences across restarts. When empty
+	// they only live in memory.
+	SilencesFile string `yaml:"silences_file,omitempty"`
+}
+
+// Window is a scheduled maintenance window for some services and/or
+// categories. It is either recurring (Schedule, a cron expression in
+// local time) or one-off (Start); either way it lasts Duration.
+type Window struct {
+	Name       string   `yaml:"name"`
+	Services   []string `yaml:"services,omitempty"`
+	Categories []string `yaml:"categories,omitempty"`
+
+	Schedule string        `yaml:"schedule,omitempty"` // e.g. "0 3 * * sun"
+	Start    string        `yaml:"start,omitempty"`    // e.g. "2026-11-01 20:00", local time
+	Duration time.Duration `yaml:"duration"`
+
+	Reason string `yaml:"reason,omitempty"`
+}
+
+// timeLayouts are the accepted formats of Window.Start; all but RFC
+// 3339 are local time.
+var timeLayouts = []string{time.RFC3339, "2006-01-02 15:04", "2006-01-02T15:04"}
+
+// ParseTime parses a one-off window's start time.
+func ParseTime(s string) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("start %q: want YYYY-MM-DD HH:MM or RFC 3339", s)
+}
+
+// window is a compiled Window.
+type window struct {
+	Window
+	cron  *Cron     // recurring windows
+	start time.Time // one-off windows
+}
+
+// compileWindow parses w. Problems are keyed by the field of w at
+// fault ("" for the window itself); w can only be used without any.
+func compileWindow(w Window) (window, []Problem) {
+	cw := window{Window: w}
+	var ps []Problem
+	switch {
+	case w.Schedule != "" && w.Start != "":
+		ps = append(ps, Problem{Message: "schedule and start are mutually exclusive"})
+	case w.Schedule != "":
+		var err error
+		if cw.cron, err = ParseCron(w.Schedule); err != nil {
+			ps = append(ps, Problem{Field: "schedule", Message: err.Error()})
+		}
+	case w.Start != "":
+		var err error
+		if cw.start, err = ParseTime(w.Start); err != nil {
+			ps = append(ps, Problem{Field: "start", Message: err.Error()})
+		}
+	default:
+		ps = append(ps, Problem{Message: "schedule (recurring) or start (one-off) is required"})
+	}
+	if w.Duration <= 0 {
+		ps = append(ps, Problem{Field: "duration", Message: "duration must be positive"})
+	}
+	return cw, ps
+}
+
+// applies reports whether the window covers svc.
+func (w *window) applies(svc models.Service) bool {
+	return slices.Contains(w.Services, svc.Name) ||
+		(svc.Category != "" && slices.ContainsFunc(w.Categories, func(c string) bool {
+			return strings.EqualFold(c, svc.Category)
+		}))
+}
+
+// activeAt returns the occurrence of the window that covers at.
+func (w *window) activeAt(at time.Time) (time.Time, bool) {
+	if w.cron != nil {
+		return w.cron.Prev(at.Local(), at.Add(-w.Duration))
+	}
+	if !at.Before(w.start) && at.Before(w.start.Add(w.Duration)) {
+		return w.start, true
+	}
+	return time.Time{}, false
+}
+
+// Silence puts a service, or every service in a category, into
+// maintenance by hand until End.
+type Silence struct {
+	ID       string    `json:"id"`
+	Service  string    `json:"service,omitempty"`
+	Category string    `json:"category,omitempty"`
+	By       string    `json:"by"`
+	Reason   string    `json:"reason"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+}
+
+func (s *Silence) applies(svc models.Service) bool {
+	return (s.Service != "" && s.Service == svc.Name) ||
+		(s.Category != "" && strings.EqualFold(s.Category, svc.Category))
+}
+
+// ErrInvalidSilence wraps the problems AddSilence rejects.
+var ErrInvalidSilence = errors.New("invalid silence")
+
+// Manager holds the maintenance windows and silences. It implements
+// health.MaintenanceSchedule and is safe for concurrent use.
+type Manager struct {
+	mu       sync.RWMutex
+	windows  []window
+	silences []Silence
+	file     string
+}
+
+// New creates a Manager for cfg and loads saved silences. It reports
+// only the first problem with a window; see Config.Validate for all.
+func New(cfg Config) (*Manager, error) {
+	m := &Manager{file: cfg.SilencesFile}
+	if err := m.SetWindows(cfg.Windows); err != nil {
+		return nil, err
+	}
+	if m.file != "" {
+		data, err := os.ReadFile(m.file)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+		case err != nil:
+			return nil, err
+		default:
+			if err := json.Unmarshal(data, &m.silences); err != nil {
+				return nil, fmt.Errorf("%s: %w", m.file, err)
+			}
+		}
+	}
+	return m, nil
+}
+
+// SetWindows replaces the scheduled windows, e.g. after a config reload.
+func (m *Manager) SetWindows(ws []Window) error {
+	compiled := make([]window, 0, len(ws))
+	for _, w := range ws {
+		cw, ps := compileWindow(w)
+		if len(ps) > 0 {
+			return fmt.Errorf("maintenance window %q: %s", w.Name, ps[0].Message)
+		}
+		compiled = append(compiled, cw)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.windows = compiled
+	return nil
+}
+
+// Active implements health.MaintenanceSchedule. Silences take
+// precedence over windows, since they say who is working on what.
+func (m *Manager) Active(svc models.Service, at time.Time) (health.Maintenance, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, s := range m.silences {
+		if s.applies(svc) && !at.Before(s.Start) && at.Before(s.End) {
+			return health.Maintenance{Name: s.ID, By: s.By, Reason: s.Reason, Start: s.Start, End: s.End}, true
+		}
+	}
+	for i := range m.windows {
+		w := &m.windows[i]
+		if !w.applies(svc) {
+			continue
+		}
+		if start, ok := w.activeAt(at); ok {
+			return health.Maintenance{Name: w.Name, Reason: w.Reason, Start: start, End: start.Add(w.Duration)}, true
+		}
+	}
+	return health.Maintenance{}, false
+}
+
+// Silences returns the silences that have not expired, soonest to end
+// first.
+func (m *Manager) Silences() []Silence {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	now := time.Now()
+	var out []Silence
+	for _, s := range m.silences {
+		if now.Before(s.End) {
+			out = append(out, s)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].End.Before(out[j].End) })
+	return out
+}
+
+// AddSilence validates s, starts it now and returns it with its ID.
+func (m *Manager) AddSilence(s Silence) (Silence, error) {
+	now := time.Now()
+	s.By = strings.TrimSpace(s.By)
+	s.Reason = strings.TrimSpace(s.Reason)
+	switch {
+	case s.Service == "" && s.Category == "":
+		return s, fmt.Errorf("%w: service or category is required", ErrInvalidSilence)
+	case s.By == "":
+		return s, fmt.Errorf("%w: by is required", ErrInvalidSilence)
+	case s.Reason == "":
+		return s, fmt.Errorf("%w: reason is required", ErrInvalidSilence)
+	case !s.End.After(now):
+		return s, fmt.Errorf("%w: end must be in the future", ErrInvalidSilence)
+	}
+	s.ID = newID()
+	s.Start = now
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.silences = append(m.expiredDroppedLocked(now), s)
+	m.saveLocked()
+	return s, nil
+}
+
+// RemoveSilence ends a silence early. It reports whether id existed.
+func (m *Manager) RemoveSilence(id string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i := slices.IndexFunc(m.silences, func(s Silence) bool { return s.ID == id })
+	if i < 0 {
+		return false
+	}
+	m.silences = slices.Delete(m.silences, i, i+1)
+	m.silences = m.expiredDroppedLocked(time.Now())
+	m.saveLocked()
+	return true
+}
+
+// expiredDroppedLocked returns the silences still running at now.
+func (m *Manager) expiredDroppedLocked(now time.Time) []Silence {
+	return slices.DeleteFunc(m.silences, func(s Silence) bool { return !now.Before(s.End) })
+}
+
+// saveLocked writes the silences to the silences file, if any.
+// Failures are logged: the silence still applies until a restart.
+func (m *Manager) saveLocked() {
+	if m.file == "" {
+		return
+	}
+	data, err := json.MarshalIndent(m.silences, "", "  ")
+	if err == nil {
+		err = writeFile(m.file, data)
+	}
+	if err != nil {
+		log.Printf("warning: could not save silences: %v", err)
+	}
+}
+
+// writeFile replaces path atomically.
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func newID() string {
+	b := make([]byte, 6)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,103 @@
+package maintenance
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/models"
+)
+
+var (
+	proxmox = models.Service{Name: "Proxmox", Category: "Infrastructure"}
+	plex    = models.Service{Name: "Plex", Category: "Media"}
+)
+
+func TestManagerWindows(t *testing.T) {
+	m, err := New(Config{Windows: []Window{
+		{Name: "patch night", Categories: []string{"infrastructure"}, Schedule: "0 3 * * sun", Duration: 2 * time.Hour, Reason: "updates"},
+		{Name: "move", Services: []string{"Plex"}, Start: "2026-03-04 20:00", Duration: time.Hour},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sunday := time.Date(2026, 3, 1, 4, 0, 0, 0, time.Local)
+	got, ok := m.Active(proxmox, sunday)
+	if !ok || got.Name != "patch night" || got.Reason != "updates" || got.By != "" {
+		t.Fatalf("Active(Proxmox, Sunday 04:00) = %+v, %v", got, ok)
+	}
+	if want := sunday.Add(time.Hour); !got.End.Equal(want) {
+		t.Fatalf("window ends %s, want %s", got.End, want)
+	}
+	if _, ok := m.Active(proxmox, sunday.Add(time.Hour)); ok {
+		t.Fatalf("window still active at its end")
+	}
+	if _, ok := m.Active(plex, sunday); ok {
+		t.Fatalf("window applied to a service outside its category")
+	}
+
+	move := time.Date(2026, 3, 4, 20, 30, 0, 0, time.Local)
+	if got, ok := m.Active(plex, move); !ok || got.Name != "move" {
+		t.Fatalf("Active(Plex, one-off) = %+v, %v", got, ok)
+	}
+	if _, ok := m.Active(plex, move.Add(time.Hour)); ok {
+		t.Fatalf("one-off window active after it ended")
+	}
+}
+
+func TestManagerSilences(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "silences.json")
+	m, err := New(Config{
+		Windows:      []Window{{Name: "nightly", Services: []string{"Proxmox"}, Schedule: "* * * * *", Duration: time.Hour}},
+		SilencesFile: file,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, bad := range []Silence{
+		{By: "alice", Reason: "x", End: time.Now().Add(time.Hour)},
+		{Service: "Proxmox", Reason: "x", End: time.Now().Add(time.Hour)},
+		{Service: "Proxmox", By: "alice", End: time.Now().Add(time.Hour)},
+		{Service: "Proxmox", By: "alice", Reason: "x", End: time.Now().Add(-time.Minute)},
+	} {
+		if _, err := m.AddSilence(bad); !errors.Is(err, ErrInvalidSilence) {
+			t.Errorf("AddSilence(%+v) = %v, want ErrInvalidSilence", bad, err)
+		}
+	}
+
+	s, err := m.AddSilence(Silence{Service: "Proxmox", By: " alice ", Reason: "kernel update", End: time.Now().Add(time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.ID == "" || s.By != "alice" || s.Start.IsZero() {
+		t.Fatalf("silence = %+v", s)
+	}
+
+	// Silences win over windows: they say who is working on it.
+	got, ok := m.Active(proxmox, time.Now())
+	if !ok || got.Name != s.ID || got.By != "alice" || got.Reason != "kernel update" {
+		t.Fatalf("Active = %+v, %v, want the silence", got, ok)
+	}
+
+	// Silences survive a restart.
+	m2, err := New(Config{SilencesFile: file})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ss := m2.Silences(); len(ss) != 1 || ss[0].ID != s.ID {
+		t.Fatalf("reloaded silences = %+v", ss)
+	}
+
+	if !m2.RemoveSilence(s.ID) {
+		t.Fatalf("RemoveSilence(%q) = false", s.ID)
+	}
+	if m2.RemoveSilence(s.ID) {
+		t.Fatalf("RemoveSilence of a removed silence = true")
+	}
+	if _, ok := m2.Active(proxmox, time.Now()); ok {
+		t.Fatalf("still silenced after RemoveSilence")
+	}
+}
@@ -0,0 +1,85 @@
+package maintenance
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/models"
+)
+
+// Problem is one invalid setting found by Validate. Field locates it
+// within the maintenance section, e.g. "windows[1].schedule", so that
+// config.Load can report its line.
+type Problem struct {
+	Field   string
+	Message string
+
+	// First is the Field of the earlier definition a duplicate clashes
+	// with, or "".
+	First string
+}
+
+// Validate checks the windows of c against the configured services and
+// returns every problem found.
+func (c Config) Validate(services []models.Service) []Problem {
+	names := make(map[string]bool, len(services))
+	categories := make(map[string]bool)
+	for _, svc := range services {
+		names[svc.Name] = true
+		categories[strings.ToLower(svc.Category)] = true
+	}
+
+	var ps []Problem
+	first := make(map[string]string, len(c.Windows))
+	for i, w := range c.Windows {
+		field := fmt.Sprintf("windows[%d]", i)
+		label := w.Name
+		add := func(key, format string, args ...any) {
+			ps = append(ps, Problem{
+				Field:   joinField(field, key),
+				Message: label + ": " + fmt.Sprintf(format, args...),
+			})
+		}
+
+		if strings.TrimSpace(w.Name) == "" {
+			label = "maintenance." + field
+			add("", "name is required")
+		} else if f, dup := first[w.Name]; dup {
+			ps = append(ps, Problem{
+				Field:   field + ".name",
+				Message: fmt.Sprintf("duplicate maintenance window %q", w.Name),
+				First:   f,
+			})
+		} else {
+			first[w.Name] = field + ".name"
+		}
+
+		if len(w.Services) == 0 && len(w.Categories) == 0 {
+			add("", "services or categories is required")
+		}
+		for j, name := range w.Services {
+			if !names[name] {
+				add(fmt.Sprintf("services[%d]", j), "services %q: no such service", name)
+			}
+		}
+		for j, cat := range w.Categories {
+			if !categories[strings.ToLower(cat)] {
+				add(fmt.Sprintf("categories[%d]", j), "categories %q: no service has this category", cat)
+			}
+		}
+
+		_, wps := compileWindow(w)
+		for _, p := range wps {
+			add(p.Field, "%s", p.Message)
+		}
+	}
+	return ps
+}
+
+// joinField appends key to the dotted path field.
+func joinField(field, key string) string {
+	if key == "" {
+		return field
+	}
+	return field + "." + key
+}
@@ -0,0 +1,319 @@
+// Package metrics exports health check results in the OpenMetrics text
+// format for Prometheus, without a client library.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/health"
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/models"
+)
+
+// ContentType is the OpenMetrics 1.0 text exposition content type.
+const ContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// latencyBuckets are the upper bounds (seconds) of the per-service
+// latency histogram and the checker's check duration histogram.
+var latencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// histogram is a cumulative histogram over latencyBuckets.
+type histogram struct {
+	counts []uint64 // per bucket, not cumulative
+	count  uint64
+	sum    float64
+}
+
+func newHistogram() *histogram {
+	return &histogram{counts: make([]uint64, len(latencyBuckets))}
+}
+
+func (h *histogram) observe(v float64) {
+	for i, le := range latencyBuckets {
+		if v <= le {
+			h.counts[i]++
+			break
+		}
+	}
+	h.count++
+	h.sum += v
+}
+
+// series is what the Collector accumulates per service.
+type series struct {
+	latency     *histogram
+	checks      uint64
+	failures    map[health.ReasonClass]uint64
+	transitions map[health.Status]uint64 // by new status
+}
+
+func newSeries() *series {
+	return &series{
+		latency:     newHistogram(),
+		failures:    make(map[health.ReasonClass]uint64),
+		transitions: make(map[health.Status]uint64),
+	}
+}
+
+// Collector accumulates counters and histograms from completed checks.
+// It implements health.Observer; register it with health.WithObserver,
+// feed it status changes with Consume and serve it with Handler.
+type Collector struct {
+	mu       sync.Mutex
+	services map[string]*series
+	duration *histogram // total time per check, including retries
+}
+
+// New returns an empty Collector.
+func New() *Collector {
+	return &Collector{
+		services: make(map[string]*series),
+		duration: newHistogram(),
+	}
+}
+
+// ObserveCheck implements health.Observer.
+func (c *Collector) ObserveCheck(svc models.Service, res health.Result, took time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := c.seriesLocked(svc.Name)
+	s.checks++
+	if res.Status.IsUp() {
+		s.latency.observe(res.Latency.Seconds())
+	} else {
+		reason := health.ClassifyError(res.Error)
+		if reason == health.ReasonNone {
+			reason = health.ReasonUnknown
+		}
+		s.failures[reason]++
+	}
+	c.duration.observe(took.Seconds())
+}
+
+// Consume counts the status changes received from events, typically
+// from health.Checker.Subscribe, until the channel is closed. The first
+// result of a service (a change from UNKNOWN) is not counted.
+func (c *Collector) Consume(events <-chan health.Event) {
+	for ev := range events {
+		if ev.Old == health.StatusUnknown {
+			continue
+		}
+		c.mu.Lock()
+		c.seriesLocked(ev.Service.Name).transitions[ev.New]++
+		c.mu.Unlock()
+	}
+}
+
+// seriesLocked returns the series of a service, creating it if needed.
+func (c *Collector) seriesLocked(name string) *series {
+	s, ok := c.services[name]
+	if !ok {
+		s = newSeries()
+		c.services[name] = s
+	}
+	return s
+}
+
+// Handler serves the metrics of checker's configured services.
+func (c *Collector) Handler(checker *health.Checker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		bw := bufio.NewWriter(w)
+		c.write(bw, checker.Services(), checker.Snapshot(), checker.Stats())
+		_ = bw.Flush()
+	})
+}
+
+// write renders every metric family. Services that are no longer
+// configured are dropped from the Collector.
+func (c *Collector) write(w *bufio.Writer, services []models.Service, results map[string]health.Result, stats health.Stats) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	configured := make(map[string]bool, len(services))
+	for _, svc := range services {
+		configured[svc.Name] = true
+	}
+	for name := range c.services {
+		if !configured[name] {
+			delete(c.services, name)
+		}
+	}
+
+	sorted := append([]models.Service(nil), services...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	family(w, "aurora_service_up", "gauge", "1 if the service is UP or DEGRADED, 0 otherwise.")
+	for _, svc := range sorted {
+		if res, ok := results[svc.Name]; ok && res.Status != health.StatusUnknown {
+			sample(w, "aurora_service_up", labels(svc), boolValue(res.Status.IsUp()))
+		}
+	}
+
+	family(w, "aurora_service_maintenance", "gauge", "1 if the service is in a maintenance window or silenced, 0 otherwise.")
+	for _, svc := range sorted {
+		if res, ok := results[svc.Name]; ok {
+			sample(w, "aurora_service_maintenance", labels(svc), boolValue(res.Status == health.StatusMaintenance))
+		}
+	}
+
+	family(w, "aurora_service_last_check_timestamp_seconds", "gauge", "Unix time of the last completed check.")
+	for _, svc := range sorted {
+		if res, ok := results[svc.Name]; ok && !res.CheckedAt.IsZero() {
+			sample(w, "aurora_service_last_check_timestamp_seconds", labels(svc), float64(res.CheckedAt.UnixNano())/1e9)
+		}
+	}
+
+	family(w, "aurora_service_consecutive_failures", "gauge", "Failed checks in a row.")
+	for _, svc := range sorted {
+		if res, ok := results[svc.Name]; ok {
+			sample(w, "aurora_service_consecutive_failures", labels(svc), float64(res.ConsecutiveFailures))
+		}
+	}
+
+	family(w, "aurora_service_latency_seconds", "histogram", "Latency of passing checks.")
+	for _, svc := range sorted {
+		if s, ok := c.services[svc.Name]; ok {
+			writeHistogram(w, "aurora_service_latency_seconds", labels(svc), s.latency)
+		}
+	}
+
+	family(w, "aurora_service_checks", "counter", "Completed checks.")
+	for _, svc := range sorted {
+		if s, ok := c.services[svc.Name]; ok {
+			sample(w, "aurora_service_checks_total", labels(svc), float64(s.checks))
+		}
+	}
+
+	family(w, "aurora_service_check_failures", "counter", "Failed checks by reason class.")
+	for _, svc := range sorted {
+		s, ok := c.services[svc.Name]
+		if !ok {
+			continue
+		}
+		reasons := make([]string, 0, len(s.failures))
+		for r := range s.failures {
+			reasons = append(reasons, string(r))
+		}
+		sort.Strings(reasons)
+		for _, r := range reasons {
+			l := append(labels(svc), label{"reason", r})
+			sample(w, "aurora_service_check_failures_total", l, float64(s.failures[health.ReasonClass(r)]))
+		}
+	}
+
+	family(w, "aurora_service_transitions", "counter", "Status changes by new status.")
+	for _, svc := range sorted {
+		s, ok := c.services[svc.Name]
+		if !ok {
+			continue
+		}
+		statuses := make([]string, 0, len(s.transitions))
+		for st := range s.transitions {
+			statuses = append(statuses, string(st))
+		}
+		sort.Strings(statuses)
+		for _, st := range statuses {
+			l := append(labels(svc), label{"status", st})
+			sample(w, "aurora_service_transitions_total", l, float64(s.transitions[health.Status(st)]))
+		}
+	}
+
+	family(w, "aurora_checker_check_duration_seconds", "histogram", "Time spent per check, including retries.")
+	writeHistogram(w, "aurora_checker_check_duration_seconds", nil, c.duration)
+
+	family(w, "aurora_checker_checks_in_flight", "gauge", "Checks currently running.")
+	sample(w, "aurora_checker_checks_in_flight", nil, float64(stats.Running))
+
+	family(w, "aurora_checker_queue_depth", "gauge", "Scheduled checks waiting for a worker.")
+	sample(w, "aurora_checker_queue_depth", nil, float64(stats.Queued))
+
+	family(w, "aurora_checker_workers", "gauge", "Size of the check worker pool.")
+	sample(w, "aurora_checker_workers", nil, float64(stats.Workers))
+
+	w.WriteString("# EOF\n")
+}
+
+type label struct{ name, value string }
+
+// labels returns the identifying labels of a service.
+func labels(svc models.Service) []label {
+	typ := svc.Type
+	if typ == "" {
+		typ = "http"
+	}
+	return []label{{"name", svc.Name}, {"type", typ}, {"category", svc.Category}}
+}
+
+func family(w *bufio.Writer, name, typ, help string) {
+	fmt.Fprintf(w, "# TYPE %s %s\n# HELP %s %s\n", name, typ, name, help)
+}
+
+func sample(w *bufio.Writer, name string, ls []label, v float64) {
+	w.WriteString(name)
+	writeLabels(w, ls)
+	w.WriteByte(' ')
+	w.WriteString(formatFloat(v))
+	w.WriteByte('\n')
+}
+
+func writeHistogram(w *bufio.Writer, name string, ls []label, h *histogram) {
+	var cum uint64
+	for i, le := range latencyBuckets {
+		cum += h.counts[i]
+		sample(w, name+"_bucket", append(ls[:len(ls):len(ls)], label{"le", formatFloat(le)}), float64(cum))
+	}
+	sample(w, name+"_bucket", append(ls[:len(ls):len(ls)], label{"le", "+Inf"}), float64(h.count))
+	sample(w, name+"_count", ls, float64(h.count))
+	sample(w, name+"_sum", ls, h.sum)
+}
+
+func writeLabels(w *bufio.Writer, ls []label) {
+	if len(ls) == 0 {
+		return
+	}
+	w.WriteByte('{')
+	for i, l := range ls {
+		if i > 0 {
+			w.WriteByte(',')
+		}
+		w.WriteString(l.name)
+		w.WriteString(`="`)
+		w.WriteString(escapeLabel(l.value))
+		w.WriteByte('"')
+	}
+	w.WriteByte('}')
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
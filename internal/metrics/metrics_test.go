@@ -0,0 +1,138 @@
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/health"
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/models"
+)
+
+func scrape(t *testing.T, h http.Handler) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); ct != ContentType {
+		t.Fatalf("content type %q", ct)
+	}
+	body, _ := io.ReadAll(rec.Body)
+	return string(body)
+}
+
+func TestCollectorExportsChecks(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/down" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer backend.Close()
+
+	services := []models.Service{
+		{Name: "Plex", URL: backend.URL, Category: "Media"},
+		{Name: `NAS "main"`, URL: backend.URL + "/down", Category: "Storage"},
+		{Name: "Router", Type: "tcp", Host: "127.0.0.1", Port: 1},
+	}
+	col := New()
+	c := health.NewChecker(services, time.Hour, time.Second, time.Second, health.WithObserver(col))
+	for _, svc := range services {
+		c.CheckNow(context.Background(), svc.Name)
+	}
+	c.CheckNow(context.Background(), "Plex")
+
+	out := scrape(t, col.Handler(c))
+
+	for _, want := range []string{
+		`# TYPE aurora_service_up gauge`,
+		`aurora_service_up{name="Plex",type="http",category="Media"} 1`,
+		`aurora_service_up{name="NAS \"main\"",type="http",category="Storage"} 0`,
+		`aurora_service_maintenance{name="Plex",type="http",category="Media"} 0`,
+		`aurora_service_consecutive_failures{name="Router",type="tcp",category=""} 1`,
+		`# TYPE aurora_service_latency_seconds histogram`,
+		`aurora_service_latency_seconds_bucket{name="Plex",type="http",category="Media",le="+Inf"} 2`,
+		`aurora_service_latency_seconds_count{name="Plex",type="http",category="Media"} 2`,
+		`# TYPE aurora_service_checks counter`,
+		`aurora_service_checks_total{name="Plex",type="http",category="Media"} 2`,
+		`aurora_service_check_failures_total{name="Router",type="tcp",category="",reason="CONN"} 1`,
+		`aurora_checker_check_duration_seconds_count 4`,
+		`aurora_checker_checks_in_flight 0`,
+		`aurora_checker_queue_depth 0`,
+		`aurora_checker_workers 16`,
+	} {
+		if !strings.Contains(out, want+"\n") {
+			t.Errorf("missing %q", want)
+		}
+	}
+	if !strings.HasSuffix(out, "# EOF\n") {
+		t.Errorf("output must end with # EOF")
+	}
+	if strings.Contains(out, `aurora_service_check_failures_total{name="Plex"`) {
+		t.Errorf("passing service must not have failure counters")
+	}
+	if t.Failed() {
+		t.Log(out)
+	}
+
+	// Removed services disappear from the output.
+	c.UpdateServices(services[:1])
+	out = scrape(t, col.Handler(c))
+	if strings.Contains(out, "Router") || strings.Contains(out, "NAS") {
+		t.Errorf("removed services still exported:\n%s", out)
+	}
+}
+
+func TestHistogramBuckets(t *testing.T) {
+	h := newHistogram()
+	for _, v := range []float64{0.001, 0.02, 0.02, 3, 60} {
+		h.observe(v)
+	}
+
+	var b strings.Builder
+	w := bufio.NewWriter(&b)
+	writeHistogram(w, "x", nil, h)
+	w.Flush()
+
+	for _, want := range []string{
+		`x_bucket{le="0.005"} 1`,
+		`x_bucket{le="0.025"} 3`,
+		`x_bucket{le="2.5"} 3`,
+		`x_bucket{le="5"} 4`,
+		`x_bucket{le="10"} 4`,
+		`x_bucket{le="+Inf"} 5`,
+		`x_count 5`,
+		`x_sum 63.041`,
+	} {
+		if !strings.Contains(b.String(), want+"\n") {
+			t.Errorf("missing %q in\n%s", want, b.String())
+		}
+	}
+}
+
+func TestCollectorCountsTransitions(t *testing.T) {
+	svc := models.Service{Name: "NAS", Type: "tcp", Category: "Storage"}
+	c := health.NewChecker([]models.Service{svc}, time.Hour, time.Second, time.Second)
+	col := New()
+
+	events := make(chan health.Event, 3)
+	events <- health.Event{Service: svc, Old: health.StatusUnknown, New: health.StatusUp}
+	events <- health.Event{Service: svc, Old: health.StatusUp, New: health.StatusDown}
+	events <- health.Event{Service: svc, Old: health.StatusDown, New: health.StatusUp}
+	close(events)
+	col.Consume(events)
+
+	out := scrape(t, col.Handler(c))
+	for _, want := range []string{
+		`# TYPE aurora_service_transitions counter`,
+		`aurora_service_transitions_total{name="NAS",type="tcp",category="Storage",status="DOWN"} 1`,
+		`aurora_service_transitions_total{name="NAS",type="tcp",category="Storage",status="UP"} 1`,
+	} {
+		if !strings.Contains(out, want+"\n") {
+			t.Errorf("missing %q in\n%s", want, out)
+		}
+	}
+}
@@ -1,19 +1,39 @@
 package models
 
+import "time"
+
 // Service represents an application or endpoint in your homelab.
 // It now supports different health check types.
 //
 // type:
 //   - "http" (default): uses URL
 //   - "tcp": uses Host + Port
+//   - "tls": uses Host + Port (default 443) and inspects the certificate
 //
 // For HTTP services:
 //   - set Type: "http" (or leave empty to default to http)
 //   - set URL
+//   - optionally set Expect to assert on the response
+//   - optionally set Headers and BasicAuth for authenticated endpoints
 //
 // For TCP services:
 //   - set Type: "tcp"
 //   - set Host and Port
+//
+// Every service may also override how it is scheduled:
+//   - Interval: how often it is checked
+//   - Timeout: per-attempt timeout
+//   - Retries: extra attempts before a failure is reported
+//   - FailureThreshold / SuccessThreshold: consecutive results needed
+//     before the displayed state flips (flap suppression)
+//
+// Unset values fall back to the defaults in config; an explicit 0
+// (e.g. "retries: 0") is kept.
+//
+// For TLS services:
+//   - set Type: "tls"
+//   - set Host (and Port if not 443)
+//   - optionally set TLS for SNI, a custom CA bundle or the warning threshold
 type Service struct {
 	Name        string `yaml:"name"`
 	Type        string `yaml:"type,omitempty"` // "http" (default) or "tcp"
@@ -24,5 +44,87 @@ type Service struct {
 	Category    string `yaml:"category,omitempty"`
 	Description string `yaml:"description,omitempty"`
 
+	// DependsOn names the services this one needs. While one of them
+	// is DOWN, failures of this service are reported as UNREACHABLE.
 	DependsOn []string `yaml:"depends_on,omitempty"`
+
+	Interval time.Duration `yaml:"interval,omitempty"` // e.g. "10s", "5m"
+	Timeout  time.Duration `yaml:"timeout,omitempty"`  // per attempt
+	Retries  int           `yaml:"retries,omitempty"`  // extra attempts on failure
+
+	// Flap suppression: how many consecutive failures turn an UP service
+	// DOWN, and how many consecutive successes bring it back UP.
+	// Zero or one means "change state immediately".
+	FailureThreshold int `yaml:"failure_threshold,omitempty"`
+	SuccessThreshold int `yaml:"success_threshold,omitempty"`
+
+	// Latency thresholds: a passing check is shown as DEGRADED when its
+	// latency exceeds LatencyWarn, or when the p95 over recent checks
+	// exceeds LatencyWarnP95.
+	LatencyWarn    time.Duration `yaml:"latency_warn,omitempty"`
+	LatencyWarnP95 time.Duration `yaml:"latency_warn_p95,omitempty"`
+
+	// Headers are sent with every HTTP check (e.g. an API token).
+	// A "Host" entry overrides the request's Host header.
+	Headers   map[string]string `yaml:"headers,omitempty"`    // used for HTTP
+	BasicAuth BasicAuth         `yaml:"basic_auth,omitempty"` // used for HTTP
+
+	Expect HTTPExpect `yaml:"expect,omitempty"` // used for HTTP
+	TLS    TLSOptions `yaml:"tls,omitempty"`    // used for TLS
+}
+
+// BasicAuth holds HTTP basic auth credentials. It is only sent when
+// Username or Password is set.
+type BasicAuth struct {
+	Username string `yaml:"username,omitempty"`
+	Password string `yaml:"password,omitempty"`
+}
+
+// IsSet reports whether credentials are configured.
+func (a BasicAuth) IsSet() bool {
+	return a.Username != "" || a.Password != ""
+}
+
+// HTTPExpect holds optional assertions for HTTP checks.
+// When a field is left empty it is not checked; with no assertions
+// at all any 2xx/3xx response counts as UP.
+type HTTPExpect struct {
+	// Status lists the accepted status codes (e.g. [200, 204]).
+	// Empty means any 2xx/3xx.
+	Status []int `yaml:"status,omitempty"`
+
+	// BodyContains requires the response body to contain this substring.
+	BodyContains string `yaml:"body_contains,omitempty"`
+
+	// BodyRegex requires the response body to match this regular expression.
+	BodyRegex string `yaml:"body_regex,omitempty"`
+
+	// JSONPath selects a value in a JSON body using dot notation,
+	// e.g. "status" or "checks.0.state". On its own it only requires
+	// the value to exist; combine with JSONEquals to compare it.
+	JSONPath   string `yaml:"json_path,omitempty"`
+	JSONEquals string `yaml:"json_equals,omitempty"`
+
+	// MaxBodyBytes caps how much of the body is read for assertions.
+	// Defaults to 1 MiB.
+	MaxBodyBytes int64 `yaml:"max_body_bytes,omitempty"`
+}
+
+// HasBodyAssertions reports whether the response body needs to be read.
+func (e HTTPExpect) HasBodyAssertions() bool {
+	return e.BodyContains != "" || e.BodyRegex != "" || e.JSONPath != ""
+}
+
+// TLSOptions configures TLS certificate checks.
+type TLSOptions struct {
+	// ServerName overrides the SNI name and the name the certificate is
+	// verified against. Defaults to Host.
+	ServerName string `yaml:"server_name,omitempty"`
+
+	// CAFile is a PEM bundle of trusted roots. Defaults to the system pool.
+	CAFile string `yaml:"ca_file,omitempty"`
+
+	// WarnDays flags the certificate when it expires within this many
+	// days. Defaults to 14.
+	WarnDays int `yaml:"warn_days,omitempty"`
 }
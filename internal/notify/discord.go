@@ -0,0 +1,94 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/health"
+)
+
+// Embed colors by status.
+const (
+	colorDown = 0xE74C3C // red
+	colorUp   = 0x2ECC71 // green
+	colorTest = 0x95A5A6 // grey
+)
+
+// discord posts an embed to a Discord channel webhook.
+// See https://discord.com/developers/docs/resources/webhook#execute-webhook.
+type discord struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+func newDiscord(t Target, client *http.Client) *discord {
+	return &discord{url: t.URL, headers: t.Headers, client: client}
+}
+
+type discordPayload struct {
+	Username string         `json:"username"`
+	Embeds   []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	Color       int            `json:"color"`
+	Timestamp   string         `json:"timestamp,omitempty"`
+	Fields      []discordField `json:"fields,omitempty"`
+}
+
+type discordField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+// Notify implements Notifier.
+func (d *discord) Notify(ctx context.Context, msg Message) error {
+	embed := discordEmbed{
+		Title:       msg.Title,
+		Description: msg.Text,
+		Color:       colorUp,
+	}
+	switch {
+	case msg.Test:
+		embed.Color = colorTest
+	case msg.New == health.StatusDown:
+		embed.Color = colorDown
+	}
+	if !msg.At.IsZero() {
+		embed.Timestamp = msg.At.UTC().Format(time.RFC3339)
+	}
+	for _, f := range messageFields(msg) {
+		embed.Fields = append(embed.Fields, discordField{Name: f.name, Value: f.value, Inline: true})
+	}
+
+	return postJSON(ctx, d.client, d.url, d.headers, discordPayload{
+		Username: "Aurora",
+		Embeds:   []discordEmbed{embed},
+	})
+}
+
+// field is a labelled detail shown by chat notifiers.
+type field struct{ name, value string }
+
+// messageFields returns the details of msg worth showing next to its text.
+func messageFields(msg Message) []field {
+	var fs []field
+	if msg.Category != "" {
+		fs = append(fs, field{"Category", msg.Category})
+	}
+	if msg.Type != "" {
+		fs = append(fs, field{"Check", msg.Type})
+	}
+	if msg.ReasonLabel != "" {
+		fs = append(fs, field{"Reason", msg.ReasonLabel})
+	}
+	if msg.Old != "" && msg.Old != msg.New {
+		fs = append(fs, field{"Was", string(msg.Old)})
+	}
+	return fs
+}
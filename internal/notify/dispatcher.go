@@ -0,0 +1,482 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/health"
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/redact"
+)
+
+// queueSize is how many notifications may wait per target; beyond that
+// they go straight to the dead-letter log.
+const queueSize = 64
+
+// ErrUnknownTarget is returned by Test for a target that is not configured.
+var ErrUnknownTarget = errors.New("unknown notification target")
+
+// target is a configured Notifier plus its delivery settings.
+type target struct {
+	name       string
+	notifier   Notifier
+	timeout    time.Duration
+	retries    int
+	retryDelay time.Duration
+	queue      chan Message
+
+	// batchWindow is set for BatchNotifiers: how long to collect
+	// messages before delivering them together.
+	batchWindow time.Duration
+
+	// secrets masks the target's token, which the telegram Bot API
+	// puts in the request URL and so in transport errors.
+	secrets *redact.Redactor
+}
+
+// Dispatcher delivers notifications for Checker events to the targets
+// chosen by the configured routes. Each target has its own queue and
+// worker, so a slow or failing target neither delays the others nor
+// reorders its own messages.
+type Dispatcher struct {
+	targets       []*target
+	routes        []*route
+	deadLetterDir string
+	redactor      *redact.Redactor
+	status        func(service string) (health.Status, bool)
+
+	deadLetterMu sync.Mutex
+}
+
+// Option customizes a Dispatcher created by New.
+type Option func(*Dispatcher)
+
+// WithRedactor masks secrets in logged errors and dead-letter entries.
+func WithRedactor(r *redact.Redactor) Option {
+	return func(d *Dispatcher) {
+		d.redactor = r
+	}
+}
+
+// WithStatus lets the Dispatcher look up a service's current status
+// before sending an escalation or reminder, so an outage whose recovery
+// event was missed (or whose service was removed) does not page forever.
+func WithStatus(lookup func(service string) (health.Status, bool)) Option {
+	return func(d *Dispatcher) {
+		d.status = lookup
+	}
+}
+
+// New creates a Dispatcher for cfg. It fails if a target cannot be
+// set up (e.g. a body template does not parse) or a route names an
+// unknown target; Config.Validate reports every problem at once.
+func New(cfg Config, opts ...Option) (*Dispatcher, error) {
+	d := &Dispatcher{deadLetterDir: cfg.DeadLetterDir}
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	client := &http.Client{}
+	byName := make(map[string]*target, len(cfg.Targets))
+	for _, t := range cfg.Targets {
+		n, err := newNotifier(t, client)
+		if err != nil {
+			return nil, fmt.Errorf("notification target %q: %w", t.Name, err)
+		}
+		retries := defaultRetries
+		if t.Retries != nil {
+			retries = *t.Retries
+		}
+		tg := &target{
+			name:       t.Name,
+			notifier:   n,
+			timeout:    orDefault(t.Timeout, defaultTimeout),
+			retries:    retries,
+			retryDelay: orDefault(t.RetryDelay, defaultRetryDelay),
+			queue:      make(chan Message, queueSize),
+			secrets:    redact.New(t.Token, t.Password),
+		}
+		if _, ok := n.(BatchNotifier); ok {
+			tg.batchWindow = orDefault(t.BatchWindow, defaultBatchWindow)
+		}
+		d.targets = append(d.targets, tg)
+		byName[t.Name] = tg
+	}
+
+	for i, r := range cfg.Routes {
+		rt, err := compileRoute(r, byName)
+		if err != nil {
+			return nil, fmt.Errorf("notification route %d: %w", i+1, err)
+		}
+		d.routes = append(d.routes, rt)
+	}
+	if len(cfg.Routes) == 0 {
+		d.routes = []*route{{targets: d.targets}}
+	}
+	return d, nil
+}
+
+// newNotifier creates the Notifier for a target's type.
+func newNotifier(t Target, client *http.Client) (Notifier, error) {
+	switch t.Type {
+	case "", "webhook":
+		return newWebhook(t, client)
+	case "ntfy":
+		return newNtfy(t, client), nil
+	case "gotify":
+		return newGotify(t, client), nil
+	case "discord":
+		return newDiscord(t, client), nil
+	case "slack":
+		return newSlack(t, client), nil
+	case "telegram":
+		return newTelegram(t, client), nil
+	case "smtp":
+		return newSMTP(t), nil
+	default:
+		return nil, fmt.Errorf("unknown type %q", t.Type)
+	}
+}
+
+func orDefault[T comparable](v, def T) T {
+	var zero T
+	if v == zero {
+		return def
+	}
+	return v
+}
+
+// Run delivers notifications for events (see ShouldNotify) until ctx
+// is cancelled or events is closed, and sends the escalations and
+// reminders of ongoing outages. Deliveries still queued or being
+// retried when ctx is cancelled are dropped.
+func (d *Dispatcher) Run(ctx context.Context, events <-chan health.Event) {
+	var wg sync.WaitGroup
+	for _, t := range d.targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.worker(ctx, t)
+		}()
+	}
+	defer wg.Wait()
+
+	// incidents are the notified outages by service name.
+	incidents := make(map[string]*incident)
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	for {
+		var wake <-chan time.Time
+		if due := nextDue(incidents); !due.IsZero() {
+			timer.Reset(time.Until(due))
+			wake = timer.C
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-events:
+			if !ok {
+				for _, t := range d.targets {
+					close(t.queue)
+				}
+				return
+			}
+			d.handle(ev, incidents, time.Now())
+		case now := <-wake:
+			d.remind(incidents, now)
+		}
+	}
+}
+
+// handle routes the notification for ev, if any, and keeps track of
+// outages until they recover. An outage that turns UNREACHABLE once
+// its root cause fails stays open, so its recovery is still sent.
+func (d *Dispatcher) handle(ev health.Event, incidents map[string]*incident, now time.Time) {
+	name := ev.Service.Name
+	inc := incidents[name]
+	switch {
+	case inc != nil && ev.New == health.StatusUnreachable:
+		inc.held = true
+		return
+	case inc != nil && ev.New == health.StatusDown:
+		inc.held = false // DOWN again after its root cause recovered
+		return
+	case ev.New != health.StatusDown:
+		delete(incidents, name)
+	}
+	recovered := inc != nil && ev.Old == health.StatusUnreachable && ev.New.IsUp()
+	if recovered {
+		ev.Duration = ev.At.Sub(inc.msg.At) // since it went DOWN
+	}
+	if !ShouldNotify(ev) && !recovered {
+		return
+	}
+
+	msg := NewMessage(ev)
+	if inc != nil {
+		// Recoveries go to whoever heard about the outage, whatever
+		// the routes say now.
+		d.enqueue(msg, inc.recipients())
+		return
+	}
+	r := d.route(msg)
+	if r == nil {
+		log.Printf("debug: no notification route for %q", msg.Title)
+		return
+	}
+	d.enqueue(msg, r.targets)
+	if msg.New == health.StatusDown {
+		incidents[name] = newIncident(r, msg, now)
+	}
+}
+
+// route returns the first route matching msg, or nil.
+func (d *Dispatcher) route(msg Message) *route {
+	for _, r := range d.routes {
+		if r.matches(msg) {
+			return r
+		}
+	}
+	return nil
+}
+
+// remind sends the escalations and reminders that are due at now.
+func (d *Dispatcher) remind(incidents map[string]*incident, now time.Time) {
+	for name, inc := range incidents {
+		due := inc.due()
+		if due.IsZero() || now.Before(due) {
+			continue
+		}
+		if d.status != nil {
+			if st, ok := d.status(name); !ok || st != health.StatusDown {
+				delete(incidents, name)
+				continue
+			}
+		}
+
+		msg := reminderMessage(inc.msg, now.Sub(inc.since))
+		told := inc.recipients()
+		if !inc.escalateAt.IsZero() && !now.Before(inc.escalateAt) {
+			d.enqueue(msg, inc.route.escalate)
+			inc.escalated = true
+			inc.escalateAt = time.Time{}
+		}
+		if !inc.repeatAt.IsZero() && !now.Before(inc.repeatAt) {
+			d.enqueue(msg, told)
+			for !inc.repeatAt.After(now) {
+				inc.repeatAt = inc.repeatAt.Add(inc.route.repeat)
+			}
+		}
+	}
+}
+
+// nextDue returns the earliest time an incident needs attention, or
+// zero if none does.
+func nextDue(incidents map[string]*incident) time.Time {
+	var next time.Time
+	for _, inc := range incidents {
+		if due := inc.due(); !due.IsZero() && (next.IsZero() || due.Before(next)) {
+			next = due
+		}
+	}
+	return next
+}
+
+// enqueue hands msg to the workers of targets.
+func (d *Dispatcher) enqueue(msg Message, targets []*target) {
+	for _, t := range targets {
+		select {
+		case t.queue <- msg:
+		default:
+			d.deadLetter(t, msg, 0, errors.New("queue full"))
+		}
+	}
+}
+
+// worker delivers a target's queued messages in order.
+func (d *Dispatcher) worker(ctx context.Context, t *target) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-t.queue:
+			if !ok {
+				return
+			}
+			batch := []Message{msg}
+			if t.batchWindow > 0 {
+				batch = collect(ctx, t, batch)
+			}
+			d.deliver(ctx, t, batch)
+		}
+	}
+}
+
+// collect adds the messages queued for t within its batch window.
+func collect(ctx context.Context, t *target, batch []Message) []Message {
+	timer := time.NewTimer(t.batchWindow)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return batch
+		case <-timer.C:
+			return batch
+		case msg, ok := <-t.queue:
+			if !ok {
+				return batch
+			}
+			batch = append(batch, msg)
+		}
+	}
+}
+
+// deliver sends msgs, retrying with exponential backoff. Once the
+// retries are used up the messages go to the dead-letter log.
+func (d *Dispatcher) deliver(ctx context.Context, t *target, msgs []Message) {
+	delay := t.retryDelay
+	for attempt := 1; ; attempt++ {
+		err := d.attempt(ctx, t, msgs...)
+		if err == nil {
+			if len(msgs) == 1 {
+				log.Printf("debug: notified %s: %s", t.name, msgs[0].Title)
+			} else {
+				log.Printf("debug: notified %s: %d changes", t.name, len(msgs))
+			}
+			return
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if attempt > t.retries {
+			for _, msg := range msgs {
+				d.deadLetter(t, msg, attempt, err)
+			}
+			return
+		}
+		log.Printf("debug: notify %s failed (attempt %d), retrying in %s: %s", t.name, attempt, delay, d.errString(t, err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// attempt makes one delivery attempt bounded by the target's timeout.
+func (d *Dispatcher) attempt(ctx context.Context, t *target, msgs ...Message) error {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	if bn, ok := t.notifier.(BatchNotifier); ok && len(msgs) > 1 {
+		return bn.NotifyBatch(ctx, msgs)
+	}
+	for _, msg := range msgs {
+		if err := t.notifier.Notify(ctx, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Targets returns the names of the configured targets.
+func (d *Dispatcher) Targets() []string {
+	names := make([]string, len(d.targets))
+	for i, t := range d.targets {
+		names[i] = t.name
+	}
+	return names
+}
+
+// Test sends a test message to the named target right away, with a
+// single attempt, and returns the (redacted) delivery error.
+func (d *Dispatcher) Test(ctx context.Context, name string) error {
+	for _, t := range d.targets {
+		if t.name == name {
+			if err := d.attempt(ctx, t, testMessage(name)); err != nil {
+				return errors.New(d.errString(t, err))
+			}
+			return nil
+		}
+	}
+	return ErrUnknownTarget
+}
+
+// errString returns err's message with secrets masked.
+func (d *Dispatcher) errString(t *target, err error) string {
+	return d.redactor.String(t.secrets.String(err.Error()))
+}
+
+// deadLetterEntry is one line of a target's dead-letter log.
+type deadLetterEntry struct {
+	FailedAt time.Time   `json:"failed_at"`
+	Target   string      `json:"target"`
+	Attempts int         `json:"attempts"`
+	Error    string      `json:"error"`
+	Message  jsonMessage `json:"message"`
+}
+
+// deadLetter records a notification that could not be delivered.
+func (d *Dispatcher) deadLetter(t *target, msg Message, attempts int, err error) {
+	reason := d.errString(t, err)
+	log.Printf("warning: notify %s: giving up on %q after %d attempts: %s", t.name, msg.Title, attempts, reason)
+
+	if d.deadLetterDir == "" {
+		return
+	}
+	line, _ := json.Marshal(deadLetterEntry{
+		FailedAt: time.Now().UTC(),
+		Target:   t.name,
+		Attempts: attempts,
+		Error:    reason,
+		Message:  toJSON(msg),
+	})
+
+	d.deadLetterMu.Lock()
+	defer d.deadLetterMu.Unlock()
+
+	if err := appendLine(d.deadLetterPath(t.name), line); err != nil {
+		log.Printf("warning: could not write dead letter for %s: %v", t.name, err)
+	}
+}
+
+// deadLetterPath returns the dead-letter log of a target.
+func (d *Dispatcher) deadLetterPath(name string) string {
+	safe := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+	return filepath.Join(d.deadLetterDir, safe+".log")
+}
+
+func appendLine(path string, line []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
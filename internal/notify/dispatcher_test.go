@@ -0,0 +1,215 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/health"
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/models"
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/redact"
+)
+
+// run feeds events to a Dispatcher and waits until it has delivered
+// (or given up on) all of them.
+func run(t *testing.T, d *Dispatcher, events ...health.Event) {
+	t.Helper()
+
+	ch := make(chan health.Event, len(events))
+	for _, ev := range events {
+		ch <- ev
+	}
+	close(ch)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d.Run(context.Background(), ch)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Run did not return")
+	}
+}
+
+func TestDispatcherDeliversTransitionsInOrder(t *testing.T) {
+	srv, reqs := newReceiver(t, http.StatusOK)
+	d, err := New(Config{Targets: []Target{{Name: "hook", URL: srv.URL, Body: "{{.Title}}"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	svc := models.Service{Name: "NAS"}
+	run(t, d,
+		health.Event{Service: svc, Old: health.StatusUnknown, New: health.StatusUp},
+		health.Event{Service: svc, Old: health.StatusUp, New: health.StatusDown},
+		health.Event{Service: svc, Old: health.StatusDown, New: health.StatusUp},
+	)
+
+	var got []string
+	for len(reqs) > 0 {
+		got = append(got, (<-reqs).body)
+	}
+	if want := "NAS is DOWN,NAS is UP"; strings.Join(got, ",") != want {
+		t.Fatalf("delivered %q, want %q", got, want)
+	}
+}
+
+func TestDispatcherRecoversOutageThatTurnedUnreachable(t *testing.T) {
+	srv, reqs := newReceiver(t, http.StatusOK)
+	d, err := New(Config{Targets: []Target{{
+		Name: "hook",
+		URL:  srv.URL,
+		Body: "{{.Title}}{{if .Duration}} after {{.Duration}}{{end}}",
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plex, jellyfin := models.Service{Name: "Plex"}, models.Service{Name: "Jellyfin"}
+	start := time.Now()
+	at := func(m int) time.Time { return start.Add(time.Duration(m) * time.Minute) }
+	run(t, d,
+		// Plex was paged before the NAS it depends on failed too.
+		health.Event{Service: plex, Old: health.StatusUp, New: health.StatusDown, At: at(0)},
+		health.Event{Service: plex, Old: health.StatusDown, New: health.StatusUnreachable, At: at(1)},
+		health.Event{Service: plex, Old: health.StatusUnreachable, New: health.StatusDown, At: at(5)},
+		health.Event{Service: plex, Old: health.StatusDown, New: health.StatusUnreachable, At: at(6)},
+		health.Event{Service: plex, Old: health.StatusUnreachable, New: health.StatusUp, At: at(10)},
+		// Jellyfin was only ever covered by the NAS alert.
+		health.Event{Service: jellyfin, Old: health.StatusUp, New: health.StatusUnreachable, At: at(1)},
+		health.Event{Service: jellyfin, Old: health.StatusUnreachable, New: health.StatusUp, At: at(10)},
+	)
+
+	var got []string
+	for len(reqs) > 0 {
+		got = append(got, (<-reqs).body)
+	}
+	if want := "Plex is DOWN,Plex is UP after 10m0s"; strings.Join(got, ",") != want {
+		t.Fatalf("delivered %q, want %q", got, want)
+	}
+}
+
+func TestDispatcherRetriesThenDeadLetters(t *testing.T) {
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	// The second target is unreachable and has a secret in its URL.
+	const secret = "s3cr3t-token"
+	dir := t.TempDir()
+	two, none := 2, 0 // no point retrying a closed port in a test
+	d, err := New(Config{
+		DeadLetterDir: dir,
+		Targets: []Target{
+			{Name: "flaky", URL: srv.URL, Retries: &two, RetryDelay: time.Millisecond},
+			{Name: "gone", URL: "http://127.0.0.1:1/hook/" + secret, Retries: &none},
+		},
+	}, WithRedactor(redact.New(secret)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	run(t, d, health.Event{Service: models.Service{Name: "NAS"}, Old: health.StatusUp, New: health.StatusDown})
+
+	if attempts != 3 {
+		t.Fatalf("flaky target got %d attempts, want 3 (1 + 2 retries)", attempts)
+	}
+
+	var entry deadLetterEntry
+	data, err := os.ReadFile(filepath.Join(dir, "flaky.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &entry); err != nil {
+		t.Fatalf("dead letter %q: %v", data, err)
+	}
+	if entry.Target != "flaky" || entry.Attempts != 3 || !strings.Contains(entry.Error, "500") || entry.Message.Title != "NAS is DOWN" {
+		t.Fatalf("dead letter = %+v", entry)
+	}
+
+	data, err = os.ReadFile(filepath.Join(dir, "gone.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), secret) || !strings.Contains(string(data), redact.Mask) {
+		t.Fatalf("dead letter should redact the target URL:\n%s", data)
+	}
+}
+
+func TestDispatcherRetriesDefaultUnlessSet(t *testing.T) {
+	none := 0
+	d, err := New(Config{Targets: []Target{
+		{Name: "default", URL: "http://127.0.0.1:1/"},
+		{Name: "once", URL: "http://127.0.0.1:1/", Retries: &none},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := d.targets[0].retries; got != defaultRetries {
+		t.Errorf("unset retries = %d, want %d", got, defaultRetries)
+	}
+	if got := d.targets[1].retries; got != 0 {
+		t.Errorf("retries: 0 = %d, want 0 (a single attempt)", got)
+	}
+}
+
+func TestTestHandler(t *testing.T) {
+	ok, reqs := newReceiver(t, http.StatusOK)
+	bad, _ := newReceiver(t, http.StatusForbidden)
+	d, err := New(Config{Targets: []Target{
+		{Name: "ok", URL: ok.URL},
+		{Name: "bad", URL: bad.URL},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("POST /api/v1/notifications/{target}/test", d.TestHandler())
+
+	for _, tt := range []struct {
+		target string
+		status int
+	}{
+		{"ok", http.StatusOK},
+		{"bad", http.StatusBadGateway},
+		{"nope", http.StatusNotFound},
+	} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/notifications/"+tt.target+"/test", nil))
+		if rec.Code != tt.status {
+			t.Errorf("%s: status %d, want %d: %s", tt.target, rec.Code, tt.status, rec.Body)
+		}
+	}
+
+	var body jsonMessage
+	if err := json.Unmarshal([]byte((<-reqs).body), &body); err != nil || !body.Test {
+		t.Fatalf("test message = %+v (%v), want test flag", body, err)
+	}
+}
+
+func TestDispatcherMasksTargetToken(t *testing.T) {
+	const token = "123456:bot-secret"
+	d, err := New(Config{Targets: []Target{
+		{Name: "bot", Type: "telegram", URL: "http://127.0.0.1:1", Token: token, ChatID: "1"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.Test(context.Background(), "bot")
+	if err == nil || strings.Contains(err.Error(), token) {
+		t.Fatalf("error = %v, want a failure without the bot token", err)
+	}
+}
@@ -0,0 +1,58 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/health"
+)
+
+// gotify sends messages to a Gotify server with an application token.
+// See https://gotify.net/api-docs#/message/createMessage.
+type gotify struct {
+	url      string
+	token    string
+	priority int
+	headers  map[string]string
+	client   *http.Client
+}
+
+func newGotify(t Target, client *http.Client) *gotify {
+	return &gotify{
+		url:      strings.TrimRight(t.URL, "/"),
+		token:    t.Token,
+		priority: t.Priority,
+		headers:  t.Headers,
+		client:   client,
+	}
+}
+
+// gotifyMessage is the createMessage request body.
+type gotifyMessage struct {
+	Title    string `json:"title"`
+	Message  string `json:"message"`
+	Priority int    `json:"priority"`
+}
+
+// Notify implements Notifier.
+func (g *gotify) Notify(ctx context.Context, msg Message) error {
+	// Gotify clients notify loudly from priority 8 up.
+	priority := 4
+	if msg.New == health.StatusDown {
+		priority = 8
+	}
+	if g.priority > 0 {
+		priority = g.priority
+	}
+
+	headers := map[string]string{"X-Gotify-Key": g.token}
+	for k, v := range g.headers {
+		headers[k] = v
+	}
+	return postJSON(ctx, g.client, g.url+"/message", headers, gotifyMessage{
+		Title:    msg.Title,
+		Message:  msg.Text,
+		Priority: priority,
+	})
+}
@@ -0,0 +1,38 @@
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+)
+
+// TestHandler serves POST /api/v1/notifications/{target}/test: it sends
+// a test message to the target and reports the outcome as JSON,
+//
+//	200 {"target": "pager", "ok": true}
+//	404 {"error": "unknown notification target"}
+//	502 {"error": "unexpected status 401 Unauthorized"}
+func (d *Dispatcher) TestHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("target")
+
+		err := d.Test(r.Context(), name)
+		switch {
+		case errors.Is(err, ErrUnknownTarget):
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		case err != nil:
+			writeJSON(w, http.StatusBadGateway, map[string]string{"error": err.Error()})
+		default:
+			writeJSON(w, http.StatusOK, map[string]any{"target": name, "ok": true})
+		}
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("error writing API response: %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// notifyJSON sends downMessage with a notifier of the given target
+// type pointed at a local receiver, and decodes the request body.
+func notifyJSON(t *testing.T, target Target, out any) request {
+	t.Helper()
+
+	srv, reqs := newReceiver(t, http.StatusOK)
+	target.URL = srv.URL
+	n, err := newNotifier(target, http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := n.Notify(context.Background(), downMessage); err != nil {
+		t.Fatal(err)
+	}
+
+	got := <-reqs
+	if got.method != http.MethodPost || got.header.Get("Content-Type") != "application/json" {
+		t.Fatalf("%s: request = %s %v", target.Type, got.method, got.header)
+	}
+	if err := json.Unmarshal([]byte(got.body), out); err != nil {
+		t.Fatalf("%s: body %q: %v", target.Type, got.body, err)
+	}
+	return got
+}
+
+func TestNtfy(t *testing.T) {
+	var body ntfyMessage
+	got := notifyJSON(t, Target{Type: "ntfy", Topic: "homelab", Tags: []string{"aurora"}}, &body)
+
+	if got.path != "/" {
+		t.Fatalf("path = %q, want / (JSON publishing)", got.path)
+	}
+	if body.Topic != "homelab" || body.Title != downMessage.Title || body.Message != downMessage.Text {
+		t.Fatalf("body = %+v", body)
+	}
+	if body.Priority != 4 || strings.Join(body.Tags, ",") != "rotating_light,aurora" {
+		t.Fatalf("priority/tags = %d %v, want 4 [rotating_light aurora]", body.Priority, body.Tags)
+	}
+}
+
+func TestGotify(t *testing.T) {
+	var body gotifyMessage
+	got := notifyJSON(t, Target{Type: "gotify", Token: "app-token", Priority: 10}, &body)
+
+	if got.path != "/message" || got.header.Get("X-Gotify-Key") != "app-token" {
+		t.Fatalf("request = %s %v", got.path, got.header)
+	}
+	if body.Title != downMessage.Title || body.Priority != 10 {
+		t.Fatalf("body = %+v", body)
+	}
+}
+
+func TestDiscord(t *testing.T) {
+	var body discordPayload
+	notifyJSON(t, Target{Type: "discord"}, &body)
+
+	if len(body.Embeds) != 1 {
+		t.Fatalf("embeds = %+v", body.Embeds)
+	}
+	e := body.Embeds[0]
+	if e.Title != downMessage.Title || e.Color != colorDown {
+		t.Fatalf("embed = %+v, want red %s", e, downMessage.Title)
+	}
+	if len(e.Fields) == 0 || e.Fields[len(e.Fields)-1] != (discordField{Name: "Was", Value: "UP", Inline: true}) {
+		t.Fatalf("fields = %+v", e.Fields)
+	}
+}
+
+func TestSlack(t *testing.T) {
+	var body slackPayload
+	notifyJSON(t, Target{Type: "slack"}, &body)
+
+	if body.Text != downMessage.Text || len(body.Blocks) != 3 {
+		t.Fatalf("body = %+v", body)
+	}
+	if b := body.Blocks[0]; b.Type != "header" || b.Text.Text != downMessage.Title {
+		t.Fatalf("header block = %+v", b)
+	}
+	if b := body.Blocks[1]; !strings.HasPrefix(b.Text.Text, ":rotating_light: ") {
+		t.Fatalf("section block = %+v", b.Text)
+	}
+}
+
+func TestTelegram(t *testing.T) {
+	var body telegramMessage
+	got := notifyJSON(t, Target{Type: "telegram", Token: "123:abc", ChatID: "-100"}, &body)
+
+	if got.path != "/bot123:abc/sendMessage" {
+		t.Fatalf("path = %q", got.path)
+	}
+	if body.ChatID != "-100" || body.ParseMode != "HTML" {
+		t.Fatalf("body = %+v", body)
+	}
+	if !strings.HasPrefix(body.Text, "<b>Plex is DOWN</b>\n") || !strings.Contains(body.Text, "connection &#34;refused&#34;") {
+		t.Fatalf("text = %q, want bold title and escaped error", body.Text)
+	}
+}
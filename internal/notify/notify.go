@@ -0,0 +1,288 @@
+// Package notify sends alerts to external services (webhooks, chat
+// tools, email) when a service changes state.
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/health"
+)
+
+// Config is the notifications section of the config file.
+type Config struct {
+	// DeadLetterDir receives one log per target (<name>.log, JSON lines)
+	// of notifications that could not be delivered after all retries.
+	// When empty, failed deliveries are only logged.
+	DeadLetterDir string `yaml:"dead_letter_dir,omitempty"`
+
+	Targets []Target `yaml:"targets,omitempty"`
+
+	// Routes decide which targets hear about which changes. Without
+	// routes every target gets every notification; with routes, changes
+	// that match none of them are not sent.
+	Routes []Route `yaml:"routes,omitempty"`
+}
+
+// Target is one notification destination.
+type Target struct {
+	Name string `yaml:"name"`
+	Type string `yaml:"type,omitempty"` // webhook (default), ntfy, gotify, discord, slack, telegram, smtp
+
+	// URL is where messages are sent: the webhook URL for webhook,
+	// discord and slack, the server for ntfy (default https://ntfy.sh)
+	// and gotify, and the Bot API base for telegram (default
+	// https://api.telegram.org).
+	URL string `yaml:"url,omitempty"`
+
+	// Headers are added to every request, e.g. ntfy access tokens.
+	Headers map[string]string `yaml:"headers,omitempty"`
+
+	// Webhook settings. Body is a Go text/template executed with a
+	// Message; when empty a JSON document describing the change is sent.
+	Method string `yaml:"method,omitempty"` // default POST
+	Body   string `yaml:"body,omitempty"`
+
+	// Token is the gotify application token or the telegram bot token.
+	Token string `yaml:"token,omitempty"`
+
+	Topic  string `yaml:"topic,omitempty"`   // ntfy
+	ChatID string `yaml:"chat_id,omitempty"` // telegram
+
+	// Priority overrides the priority of ntfy (1-5) and gotify (0-10)
+	// messages; by default outages are sent with a higher priority
+	// than recoveries.
+	Priority int `yaml:"priority,omitempty"`
+
+	// Tags are added to ntfy messages, after the status emoji tag.
+	Tags []string `yaml:"tags,omitempty"`
+
+	// SMTP settings. TLSMode is starttls (default), implicit (default
+	// for port 465) or none; Port defaults to 587, or 465 for implicit
+	// TLS. Username enables PLAIN auth.
+	Host     string   `yaml:"host,omitempty"`
+	Port     int      `yaml:"port,omitempty"`
+	TLSMode  string   `yaml:"tls_mode,omitempty"`
+	Username string   `yaml:"username,omitempty"`
+	Password string   `yaml:"password,omitempty"`
+	From     string   `yaml:"from,omitempty"`
+	To       []string `yaml:"to,omitempty"`
+
+	// BatchWindow collects the changes of this long into one message
+	// (smtp only, default 30s), so an outage that takes down twenty
+	// services sends one email rather than twenty.
+	BatchWindow time.Duration `yaml:"batch_window,omitempty"`
+
+	// Delivery: each attempt is bounded by Timeout; failed attempts are
+	// retried Retries times, waiting RetryDelay and doubling it after
+	// each failure. Retries is a pointer so that an explicit 0 (send
+	// once) is told apart from unset.
+	Timeout    time.Duration `yaml:"timeout,omitempty"`     // default 10s
+	Retries    *int          `yaml:"retries,omitempty"`     // default 3
+	RetryDelay time.Duration `yaml:"retry_delay,omitempty"` // default 2s
+}
+
+// Delivery defaults for targets that leave them unset.
+const (
+	defaultTimeout    = 10 * time.Second
+	defaultRetries    = 3
+	defaultRetryDelay = 2 * time.Second
+
+	defaultBatchWindow = 30 * time.Second
+)
+
+// Notifier delivers a Message to one destination.
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
+
+// BatchNotifier is a Notifier that can deliver several messages as
+// one. Targets whose notifier implements it get their messages in
+// batches collected over the target's batch window.
+type BatchNotifier interface {
+	Notifier
+	NotifyBatch(ctx context.Context, msgs []Message) error
+}
+
+// Message describes a state change in a form ready for notifications.
+// It is the data passed to body templates, e.g.
+//
+//	{"text": {{json .Text}}, "down": {{eq .New "DOWN"}}}
+type Message struct {
+	Service  string
+	Category string
+	Type     string
+
+	Old health.Status
+	New health.Status
+
+	Reason      health.ReasonClass
+	ReasonLabel string // e.g. "Timeout", see health.ReasonPresentation
+	Error       string
+
+	At       time.Time
+	Duration time.Duration // time spent in Old, zero if unknown
+
+	// Test is set for messages sent from the test endpoint.
+	Test bool
+
+	// Reminder is set for escalations and repeats of an outage that is
+	// still going on; Duration is how long it has been DOWN.
+	Reminder bool
+
+	// Affected lists the services depending on one that went DOWN.
+	// They turn UNREACHABLE rather than sending their own alerts.
+	Affected []string
+
+	Title string // e.g. "Plex is DOWN"
+	Text  string // one line with the reason or how long it was down
+}
+
+// ShouldNotify reports whether ev is worth a notification: a service
+// going DOWN, or coming back from DOWN. The first result of a service
+// (from UNKNOWN, e.g. right after startup) is not, and neither is going
+// into or out of MAINTENANCE unless the service is DOWN afterwards. An
+// UNREACHABLE service is covered by the alert for its root cause.
+func ShouldNotify(ev health.Event) bool {
+	if ev.Old == health.StatusUnknown {
+		return false
+	}
+	return ev.New == health.StatusDown || (ev.Old == health.StatusDown && ev.New.IsUp())
+}
+
+// NewMessage builds the Message for ev.
+func NewMessage(ev health.Event) Message {
+	typ := ev.Service.Type
+	if typ == "" {
+		typ = "http"
+	}
+	label, _ := health.ReasonPresentation(ev.Reason)
+
+	m := Message{
+		Service:     ev.Service.Name,
+		Category:    ev.Service.Category,
+		Type:        typ,
+		Old:         ev.Old,
+		New:         ev.New,
+		Reason:      ev.Reason,
+		ReasonLabel: label,
+		Error:       ev.Error,
+		At:          ev.At,
+		Duration:    ev.Duration,
+		Affected:    ev.Affected,
+	}
+
+	m.Title = m.Service + " is " + string(m.New)
+	switch {
+	case m.New == health.StatusDown && m.ReasonLabel != "":
+		m.Text = m.Title + " (" + m.ReasonLabel + "): " + m.Error
+	case m.New == health.StatusDown:
+		m.Text = m.Title
+	case (m.Old == health.StatusDown || m.Old == health.StatusUnreachable) && m.Duration > 0:
+		m.Text = m.Title + " again after " + formatDuration(m.Duration) + " down"
+	default:
+		m.Text = m.Title + " again"
+	}
+	m.Text += affectsNote(m.Affected)
+	return m
+}
+
+// maxAffected caps the services named in affectsNote.
+const maxAffected = 5
+
+// affectsNote renders the dependents of a service that went DOWN for
+// the end of Message.Text, e.g. " • Affects Plex, Jellyfin".
+func affectsNote(affected []string) string {
+	if len(affected) == 0 {
+		return ""
+	}
+	if len(affected) <= maxAffected {
+		return " • Affects " + strings.Join(affected, ", ")
+	}
+	return " • Affects " + strings.Join(affected[:maxAffected], ", ") +
+		" and " + strconv.Itoa(len(affected)-maxAffected) + " more"
+}
+
+// testMessage is sent by Dispatcher.Test.
+func testMessage(target string) Message {
+	return Message{
+		Service: "Aurora",
+		Old:     health.StatusUp,
+		New:     health.StatusUp,
+		At:      time.Now(),
+		Test:    true,
+		Title:   "Test notification",
+		Text:    "Test notification from Aurora to " + target,
+	}
+}
+
+// ParseTemplate parses a body template. Besides the text/template
+// builtins it provides json, which renders any value as JSON so strings
+// can be embedded in JSON bodies safely.
+func ParseTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(template.FuncMap{
+		"json": func(v any) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+	}).Parse(text)
+}
+
+// jsonMessage is the JSON form of a Message, used as the default
+// webhook body and in dead-letter logs.
+type jsonMessage struct {
+	Service         string    `json:"service"`
+	Category        string    `json:"category,omitempty"`
+	Type            string    `json:"type,omitempty"`
+	Old             string    `json:"old"`
+	New             string    `json:"new"`
+	Reason          string    `json:"reason,omitempty"`
+	Error           string    `json:"error,omitempty"`
+	At              time.Time `json:"at"`
+	DurationSeconds float64   `json:"duration_seconds,omitempty"`
+	Test            bool      `json:"test,omitempty"`
+	Reminder        bool      `json:"reminder,omitempty"`
+	Affected        []string  `json:"affected,omitempty"`
+	Title           string    `json:"title"`
+	Text            string    `json:"text"`
+}
+
+func toJSON(m Message) jsonMessage {
+	return jsonMessage{
+		Service:         m.Service,
+		Category:        m.Category,
+		Type:            m.Type,
+		Old:             string(m.Old),
+		New:             string(m.New),
+		Reason:          string(m.Reason),
+		Error:           m.Error,
+		At:              m.At.UTC(),
+		DurationSeconds: m.Duration.Seconds(),
+		Test:            m.Test,
+		Reminder:        m.Reminder,
+		Affected:        m.Affected,
+		Title:           m.Title,
+		Text:            m.Text,
+	}
+}
+
+// formatDuration renders a duration compactly, e.g. "2h15m", "5m" or "45s".
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	switch {
+	case d >= time.Hour:
+		out := strconv.Itoa(int(d/time.Hour)) + "h"
+		if m := (d % time.Hour) / time.Minute; m > 0 {
+			out += strconv.Itoa(int(m)) + "m"
+		}
+		return out
+	case d >= time.Minute:
+		return strconv.Itoa(int(d/time.Minute)) + "m"
+	default:
+		return strconv.Itoa(int(d/time.Second)) + "s"
+	}
+}
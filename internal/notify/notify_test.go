@@ -0,0 +1,74 @@
+package notify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/health"
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/models"
+)
+
+func TestShouldNotify(t *testing.T) {
+	tests := []struct {
+		old, new health.Status
+		want     bool
+	}{
+		{health.StatusUp, health.StatusDown, true},
+		{health.StatusDegraded, health.StatusDown, true},
+		{health.StatusDown, health.StatusUp, true},
+		{health.StatusDown, health.StatusDegraded, true},
+		{health.StatusUp, health.StatusDegraded, false},
+		{health.StatusUnknown, health.StatusDown, false},
+		{health.StatusUnknown, health.StatusUp, false},
+		{health.StatusDown, health.StatusMaintenance, false},
+		{health.StatusMaintenance, health.StatusUp, false},
+		{health.StatusMaintenance, health.StatusDown, true},
+		{health.StatusUp, health.StatusUnreachable, false},
+		{health.StatusDown, health.StatusUnreachable, false},
+		{health.StatusUnreachable, health.StatusUp, false},
+		{health.StatusUnreachable, health.StatusDown, true},
+	}
+	for _, tt := range tests {
+		if got := ShouldNotify(health.Event{Old: tt.old, New: tt.new}); got != tt.want {
+			t.Errorf("ShouldNotify(%s -> %s) = %v, want %v", tt.old, tt.new, got, tt.want)
+		}
+	}
+}
+
+func TestNewMessage(t *testing.T) {
+	svc := models.Service{Name: "Plex", Category: "Media"}
+
+	down := NewMessage(health.Event{
+		Service: svc,
+		Old:     health.StatusUp,
+		New:     health.StatusDown,
+		Reason:  health.ReasonTimeout,
+		Error:   "dial tcp: i/o timeout",
+	})
+	if down.Type != "http" || down.ReasonLabel != "Timeout" {
+		t.Fatalf("message = %+v", down)
+	}
+	if want := "Plex is DOWN (Timeout): dial tcp: i/o timeout"; down.Text != want {
+		t.Fatalf("text = %q, want %q", down.Text, want)
+	}
+
+	up := NewMessage(health.Event{
+		Service:  svc,
+		Old:      health.StatusDown,
+		New:      health.StatusUp,
+		Duration: 2*time.Hour + 15*time.Minute + 10*time.Second,
+	})
+	if want := "Plex is UP again after 2h15m down"; up.Text != want {
+		t.Fatalf("text = %q, want %q", up.Text, want)
+	}
+
+	host := NewMessage(health.Event{
+		Service:  models.Service{Name: "Proxmox", Type: "tcp"},
+		Old:      health.StatusUp,
+		New:      health.StatusDown,
+		Affected: []string{"A", "B", "C", "D", "E", "F", "G"},
+	})
+	if want := "Proxmox is DOWN • Affects A, B, C, D, E and 2 more"; host.Text != want {
+		t.Fatalf("text = %q, want %q", host.Text, want)
+	}
+}
@@ -0,0 +1,66 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/health"
+)
+
+// defaultNtfyURL is the public ntfy server.
+const defaultNtfyURL = "https://ntfy.sh"
+
+// ntfy publishes to a topic with ntfy's JSON API.
+// See https://docs.ntfy.sh/publish/#publish-as-json.
+type ntfy struct {
+	url      string
+	topic    string
+	priority int
+	tags     []string
+	headers  map[string]string
+	client   *http.Client
+}
+
+func newNtfy(t Target, client *http.Client) *ntfy {
+	return &ntfy{
+		url:      strings.TrimRight(orDefault(t.URL, defaultNtfyURL), "/"),
+		topic:    t.Topic,
+		priority: t.Priority,
+		tags:     t.Tags,
+		headers:  t.Headers,
+		client:   client,
+	}
+}
+
+// ntfyMessage is the JSON publish request.
+type ntfyMessage struct {
+	Topic    string   `json:"topic"`
+	Title    string   `json:"title"`
+	Message  string   `json:"message"`
+	Priority int      `json:"priority"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+// Notify implements Notifier.
+func (n *ntfy) Notify(ctx context.Context, msg Message) error {
+	// Priorities: 5 max, 4 high, 3 default.
+	priority, tag := 3, "white_check_mark"
+	if msg.New == health.StatusDown {
+		priority, tag = 4, "rotating_light"
+	}
+	if msg.Test {
+		tag = "test_tube"
+	}
+	if n.priority > 0 {
+		priority = n.priority
+	}
+
+	return postJSON(ctx, n.client, n.url+"/", n.headers, ntfyMessage{
+		Topic:    n.topic,
+		Title:    msg.Title,
+		Message:  msg.Text,
+		Priority: priority,
+		Tags:     append([]string{tag}, n.tags...),
+	})
+}
@@ -0,0 +1,250 @@
+package notify
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/health"
+)
+
+// Route sends the outages it matches to a set of targets, optionally
+// escalating to more targets and repeating until the service recovers.
+// Routes are tried in order and the first match wins.
+type Route struct {
+	Match   Match    `yaml:"match,omitempty"`
+	Targets []string `yaml:"targets"`
+
+	// Escalate notifies more targets when the service is still DOWN
+	// after a while.
+	Escalate *Escalation `yaml:"escalate,omitempty"`
+
+	// Repeat re-sends a reminder this often while the service is DOWN.
+	Repeat time.Duration `yaml:"repeat,omitempty"`
+}
+
+// Escalation is the second stage of a Route.
+type Escalation struct {
+	After   time.Duration `yaml:"after"`
+	Targets []string      `yaml:"targets"`
+}
+
+// Match selects the changes a Route handles; empty fields match
+// anything. Reason only matches outages: recoveries go to whoever was
+// told about the outage.
+type Match struct {
+	Service  string `yaml:"service,omitempty"`  // name glob, e.g. "plex*"
+	Category string `yaml:"category,omitempty"` // e.g. Media
+	Type     string `yaml:"type,omitempty"`     // check type, e.g. http
+	Reason   string `yaml:"reason,omitempty"`   // reason class, e.g. timeout
+	Time     string `yaml:"time,omitempty"`     // local time of day, e.g. 22:00-07:00
+}
+
+// TimeRange is a span of the day in minutes since midnight. Ranges
+// with From after To wrap around midnight.
+type TimeRange struct {
+	From, To int
+}
+
+// ParseTimeRange parses "HH:MM-HH:MM".
+func ParseTimeRange(s string) (TimeRange, error) {
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		return TimeRange{}, fmt.Errorf("time %q: want HH:MM-HH:MM", s)
+	}
+	var r TimeRange
+	var err error
+	if r.From, err = parseClock(from); err != nil {
+		return TimeRange{}, fmt.Errorf("time %q: %w", s, err)
+	}
+	if r.To, err = parseClock(to); err != nil {
+		return TimeRange{}, fmt.Errorf("time %q: %w", s, err)
+	}
+	if r.From == r.To {
+		return TimeRange{}, fmt.Errorf("time %q: empty range", s)
+	}
+	return r, nil
+}
+
+// parseClock parses "HH:MM" into minutes since midnight.
+func parseClock(s string) (int, error) {
+	hh, mm, ok := strings.Cut(strings.TrimSpace(s), ":")
+	h, herr := strconv.Atoi(hh)
+	m, merr := strconv.Atoi(mm)
+	if !ok || herr != nil || merr != nil || h < 0 || h > 24 || m < 0 || m > 59 || (h == 24 && m != 0) {
+		return 0, fmt.Errorf("bad clock time %q", strings.TrimSpace(s))
+	}
+	return h*60 + m, nil
+}
+
+// Contains reports whether t's local time of day is in r. From is
+// inclusive and To exclusive.
+func (r TimeRange) Contains(t time.Time) bool {
+	m := t.Hour()*60 + t.Minute()
+	if r.From < r.To {
+		return m >= r.From && m < r.To
+	}
+	return m >= r.From || m < r.To
+}
+
+// route is a compiled Route.
+type route struct {
+	match   Match
+	during  *TimeRange // nil: any time
+	targets []*target
+
+	escalate []*target
+	after    time.Duration
+	repeat   time.Duration
+}
+
+// compileRoute resolves r's target names.
+func compileRoute(r Route, byName map[string]*target) (*route, error) {
+	rt := &route{match: r.Match, repeat: r.Repeat}
+	if r.Match.Service != "" {
+		if _, err := path.Match(r.Match.Service, ""); err != nil {
+			return nil, fmt.Errorf("service %q: %w", r.Match.Service, err)
+		}
+	}
+	if r.Match.Time != "" {
+		tr, err := ParseTimeRange(r.Match.Time)
+		if err != nil {
+			return nil, err
+		}
+		rt.during = &tr
+	}
+
+	var err error
+	if rt.targets, err = lookupTargets(r.Targets, byName); err != nil {
+		return nil, err
+	}
+	if r.Escalate != nil {
+		rt.after = r.Escalate.After
+		if rt.escalate, err = lookupTargets(r.Escalate.Targets, byName); err != nil {
+			return nil, err
+		}
+	}
+	return rt, nil
+}
+
+func lookupTargets(names []string, byName map[string]*target) ([]*target, error) {
+	out := make([]*target, 0, len(names))
+	for _, name := range names {
+		t, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown target %q", name)
+		}
+		out = append(out, t)
+	}
+	return out, nil
+}
+
+// matches reports whether msg is one for r.
+func (r *route) matches(msg Message) bool {
+	m := r.match
+	if m.Service != "" {
+		if ok, _ := path.Match(strings.ToLower(m.Service), strings.ToLower(msg.Service)); !ok {
+			return false
+		}
+	}
+	if m.Category != "" && !strings.EqualFold(m.Category, msg.Category) {
+		return false
+	}
+	if m.Type != "" && !strings.EqualFold(m.Type, msg.Type) {
+		return false
+	}
+	if m.Reason != "" && !strings.EqualFold(m.Reason, string(msg.Reason)) {
+		return false
+	}
+	if r.during != nil {
+		at := msg.At
+		if at.IsZero() {
+			at = time.Now()
+		}
+		if !r.during.Contains(at.Local()) {
+			return false
+		}
+	}
+	return true
+}
+
+// incident is a notified outage that has not recovered yet.
+type incident struct {
+	route *route
+	msg   Message   // the DOWN notification
+	since time.Time // when it was sent
+
+	escalated  bool
+	escalateAt time.Time // zero: nothing (more) to escalate
+	repeatAt   time.Time // zero: no reminders
+
+	// held pauses escalations and reminders while the service is
+	// UNREACHABLE: the alert for its root cause covers it then.
+	held bool
+}
+
+func newIncident(r *route, msg Message, now time.Time) *incident {
+	inc := &incident{route: r, msg: msg, since: now}
+	if len(r.escalate) > 0 {
+		inc.escalateAt = now.Add(r.after)
+	}
+	if r.repeat > 0 {
+		inc.repeatAt = now.Add(r.repeat)
+	}
+	return inc
+}
+
+// due returns when the incident next needs attention, zero for never.
+func (inc *incident) due() time.Time {
+	switch {
+	case inc.held:
+		return time.Time{}
+	case inc.escalateAt.IsZero():
+		return inc.repeatAt
+	case inc.repeatAt.IsZero() || inc.escalateAt.Before(inc.repeatAt):
+		return inc.escalateAt
+	default:
+		return inc.repeatAt
+	}
+}
+
+// recipients are the targets that have been told about the outage.
+func (inc *incident) recipients() []*target {
+	if !inc.escalated {
+		return inc.route.targets
+	}
+	out := append([]*target(nil), inc.route.targets...)
+	for _, t := range inc.route.escalate {
+		if !containsTarget(out, t) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func containsTarget(ts []*target, t *target) bool {
+	for _, x := range ts {
+		if x == t {
+			return true
+		}
+	}
+	return false
+}
+
+// reminderMessage is sent while a service stays DOWN, for escalations
+// and repeats.
+func reminderMessage(down Message, d time.Duration) Message {
+	m := down
+	m.Old = health.StatusDown
+	m.Duration = d
+	m.Reminder = true
+	m.Title = m.Service + " is still " + string(health.StatusDown)
+	m.Text = m.Title + " after " + formatDuration(d)
+	if m.ReasonLabel != "" {
+		m.Text += " (" + m.ReasonLabel + "): " + m.Error
+	}
+	m.Text += affectsNote(m.Affected)
+	return m
+}
@@ -0,0 +1,210 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/health"
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/models"
+)
+
+func TestTimeRange(t *testing.T) {
+	at := func(hh, mm int) time.Time { return time.Date(2026, 1, 1, hh, mm, 0, 0, time.Local) }
+
+	tests := []struct {
+		spec string
+		at   time.Time
+		want bool
+	}{
+		{"08:00-22:00", at(8, 0), true},
+		{"08:00-22:00", at(21, 59), true},
+		{"08:00-22:00", at(22, 0), false},
+		{"22:00-07:00", at(23, 30), true},
+		{"22:00-07:00", at(3, 0), true},
+		{"22:00-07:00", at(12, 0), false},
+		{"00:00-24:00", at(23, 59), true},
+	}
+	for _, tt := range tests {
+		r, err := ParseTimeRange(tt.spec)
+		if err != nil {
+			t.Fatalf("ParseTimeRange(%q): %v", tt.spec, err)
+		}
+		if got := r.Contains(tt.at); got != tt.want {
+			t.Errorf("%s contains %s = %v, want %v", tt.spec, tt.at.Format("15:04"), got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"8-22", "08:00", "25:00-01:00", "10:00-10:00", "08:60-09:00"} {
+		if _, err := ParseTimeRange(bad); err == nil {
+			t.Errorf("ParseTimeRange(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestDispatcherRoutesFirstMatch(t *testing.T) {
+	media, mediaReqs := newReceiver(t, http.StatusOK)
+	night, nightReqs := newReceiver(t, http.StatusOK)
+	day, dayReqs := newReceiver(t, http.StatusOK)
+
+	d, err := New(Config{
+		Targets: []Target{
+			{Name: "media", URL: media.URL, Body: "{{.Title}}"},
+			{Name: "night", URL: night.URL, Body: "{{.Title}}"},
+			{Name: "day", URL: day.URL, Body: "{{.Title}}"},
+		},
+		Routes: []Route{
+			{Match: Match{Service: "plex*", Reason: "timeout"}, Targets: []string{"media"}},
+			{Match: Match{Time: "22:00-07:00"}, Targets: []string{"night"}},
+			{Targets: []string{"day"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	at := func(hh int) time.Time { return time.Date(2026, 1, 1, hh, 0, 0, 0, time.Local) }
+	down := func(name string, reason health.ReasonClass, hour int) health.Event {
+		return health.Event{Service: models.Service{Name: name}, Old: health.StatusUp, New: health.StatusDown, Reason: reason, At: at(hour)}
+	}
+	up := func(name string, hour int) health.Event {
+		return health.Event{Service: models.Service{Name: name}, Old: health.StatusDown, New: health.StatusUp, At: at(hour)}
+	}
+	run(t, d,
+		down("Plex", health.ReasonTimeout, 12),
+		down("NAS", health.ReasonConn, 23),
+		down("Plex Backup", health.ReasonConn, 12),
+		up("NAS", 8), // recovers in the day, but night heard about the outage
+		up("Plex", 13),
+	)
+
+	drain := func(reqs <-chan request) []string {
+		var got []string
+		for len(reqs) > 0 {
+			got = append(got, (<-reqs).body)
+		}
+		return got
+	}
+	for _, tt := range []struct {
+		name string
+		got  []string
+		want []string
+	}{
+		{"media", drain(mediaReqs), []string{"Plex is DOWN", "Plex is UP"}},
+		{"night", drain(nightReqs), []string{"NAS is DOWN", "NAS is UP"}},
+		{"day", drain(dayReqs), []string{"Plex Backup is DOWN"}},
+	} {
+		if len(tt.got) != len(tt.want) {
+			t.Errorf("%s got %q, want %q", tt.name, tt.got, tt.want)
+			continue
+		}
+		for i := range tt.want {
+			if tt.got[i] != tt.want[i] {
+				t.Errorf("%s got %q, want %q", tt.name, tt.got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+// recvBody waits for the next request body on reqs.
+func recvBody(t *testing.T, reqs <-chan request) string {
+	t.Helper()
+	select {
+	case r := <-reqs:
+		return r.body
+	case <-time.After(5 * time.Second):
+		t.Fatal("no request received")
+		return ""
+	}
+}
+
+func TestDispatcherEscalatesAndRepeats(t *testing.T) {
+	first, firstReqs := newReceiver(t, http.StatusOK)
+	second, secondReqs := newReceiver(t, http.StatusOK)
+
+	d, err := New(Config{
+		Targets: []Target{
+			{Name: "chat", URL: first.URL, Body: "{{.Title}}"},
+			{Name: "pager", URL: second.URL, Body: "{{.Title}}"},
+		},
+		Routes: []Route{{
+			Targets:  []string{"chat"},
+			Escalate: &Escalation{After: 50 * time.Millisecond, Targets: []string{"pager"}},
+			Repeat:   150 * time.Millisecond,
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := make(chan health.Event, 4)
+	go d.Run(ctx, events)
+
+	svc := models.Service{Name: "NAS"}
+	events <- health.Event{Service: svc, Old: health.StatusUp, New: health.StatusDown}
+
+	if got := recvBody(t, firstReqs); got != "NAS is DOWN" {
+		t.Fatalf("chat got %q first", got)
+	}
+	if got := recvBody(t, secondReqs); got != "NAS is still DOWN" {
+		t.Fatalf("pager got %q, want the escalation", got)
+	}
+	// The repeat goes to everyone who has been told, pager included.
+	if got := recvBody(t, firstReqs); got != "NAS is still DOWN" {
+		t.Fatalf("chat got %q, want a reminder", got)
+	}
+	if got := recvBody(t, secondReqs); got != "NAS is still DOWN" {
+		t.Fatalf("pager got %q, want a reminder", got)
+	}
+
+	events <- health.Event{Service: svc, Old: health.StatusDown, New: health.StatusUp}
+	for name, reqs := range map[string]<-chan request{"chat": firstReqs, "pager": secondReqs} {
+		for {
+			got := recvBody(t, reqs)
+			if got == "NAS is UP" {
+				break
+			}
+			if got != "NAS is still DOWN" {
+				t.Fatalf("%s got %q, want the recovery", name, got)
+			}
+		}
+	}
+
+	// No more reminders once the service is back.
+	time.Sleep(300 * time.Millisecond)
+	if len(firstReqs)+len(secondReqs) > 0 {
+		t.Fatalf("reminders sent after recovery")
+	}
+}
+
+func TestDispatcherStopsRemindingWhenNoLongerDown(t *testing.T) {
+	srv, reqs := newReceiver(t, http.StatusOK)
+	d, err := New(Config{
+		Targets: []Target{{Name: "chat", URL: srv.URL, Body: "{{.Title}}"}},
+		Routes:  []Route{{Targets: []string{"chat"}, Repeat: 50 * time.Millisecond}},
+	}, WithStatus(func(string) (health.Status, bool) {
+		// The recovery event was missed (or the service removed).
+		return health.StatusUp, true
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := make(chan health.Event, 1)
+	go d.Run(ctx, events)
+
+	events <- health.Event{Service: models.Service{Name: "NAS"}, Old: health.StatusUp, New: health.StatusDown}
+	if got := recvBody(t, reqs); got != "NAS is DOWN" {
+		t.Fatalf("got %q", got)
+	}
+	time.Sleep(200 * time.Millisecond)
+	if len(reqs) > 0 {
+		t.Fatalf("sent %q for a service that is not DOWN", (<-reqs).body)
+	}
+}
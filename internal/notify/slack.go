@@ -0,0 +1,68 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/health"
+)
+
+// slack posts Block Kit messages to a Slack incoming webhook.
+// See https://api.slack.com/messaging/webhooks.
+type slack struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+func newSlack(t Target, client *http.Client) *slack {
+	return &slack{url: t.URL, headers: t.Headers, client: client}
+}
+
+type slackPayload struct {
+	Text   string       `json:"text"` // fallback for notifications
+	Blocks []slackBlock `json:"blocks"`
+}
+
+type slackBlock struct {
+	Type     string      `json:"type"`
+	Text     *slackText  `json:"text,omitempty"`
+	Elements []slackText `json:"elements,omitempty"`
+}
+
+type slackText struct {
+	Type string `json:"type"` // "plain_text" or "mrkdwn"
+	Text string `json:"text"`
+}
+
+// slackEscaper escapes the characters Slack treats as markup.
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// Notify implements Notifier.
+func (s *slack) Notify(ctx context.Context, msg Message) error {
+	icon := ":white_check_mark:"
+	switch {
+	case msg.Test:
+		icon = ":test_tube:"
+	case msg.New == health.StatusDown:
+		icon = ":rotating_light:"
+	}
+
+	blocks := []slackBlock{
+		{Type: "header", Text: &slackText{Type: "plain_text", Text: msg.Title}},
+		{Type: "section", Text: &slackText{Type: "mrkdwn", Text: icon + " " + slackEscaper.Replace(msg.Text)}},
+	}
+	if fs := messageFields(msg); len(fs) > 0 {
+		ctxBlock := slackBlock{Type: "context"}
+		for _, f := range fs {
+			ctxBlock.Elements = append(ctxBlock.Elements, slackText{
+				Type: "mrkdwn",
+				Text: "*" + f.name + ":* " + slackEscaper.Replace(f.value),
+			})
+		}
+		blocks = append(blocks, ctxBlock)
+	}
+
+	return postJSON(ctx, s.client, s.url, s.headers, slackPayload{Text: msg.Text, Blocks: blocks})
+}
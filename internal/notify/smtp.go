@@ -0,0 +1,321 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/health"
+)
+
+// TLS modes of smtp targets.
+const (
+	TLSModeStartTLS = "starttls" // plain connection upgraded with STARTTLS (port 587)
+	TLSModeImplicit = "implicit" // TLS from the first byte (port 465)
+	TLSModeNone     = "none"     // no encryption, e.g. a local relay
+)
+
+// smtpMailer sends messages as multipart (plain text and HTML) email.
+type smtpMailer struct {
+	addr     string // host:port
+	host     string
+	username string
+	password string
+	from     string   // From header, e.g. "Aurora <aurora@example.com>"
+	to       []string // To header
+	envFrom  string   // bare addresses for MAIL FROM and RCPT TO
+	envTo    []string
+	tlsMode  string
+
+	// tlsConfig is used for STARTTLS and implicit TLS; tests swap in
+	// their own roots.
+	tlsConfig *tls.Config
+}
+
+func newSMTP(t Target) *smtpMailer {
+	mode := t.TLSMode
+	if mode == "" {
+		mode = TLSModeStartTLS
+		if t.Port == 465 {
+			mode = TLSModeImplicit
+		}
+	}
+	port := t.Port
+	if port == 0 {
+		port = 587
+		if mode == TLSModeImplicit {
+			port = 465
+		}
+	}
+
+	m := &smtpMailer{
+		addr:      net.JoinHostPort(t.Host, strconv.Itoa(port)),
+		host:      t.Host,
+		username:  t.Username,
+		password:  t.Password,
+		from:      t.From,
+		to:        t.To,
+		envFrom:   bareAddress(t.From),
+		tlsMode:   mode,
+		tlsConfig: &tls.Config{ServerName: t.Host},
+	}
+	for _, to := range t.To {
+		m.envTo = append(m.envTo, bareAddress(to))
+	}
+	return m
+}
+
+// bareAddress returns the address part of "Name <user@host>"; config
+// validation has already rejected addresses that do not parse.
+func bareAddress(s string) string {
+	if a, err := mail.ParseAddress(s); err == nil {
+		return a.Address
+	}
+	return s
+}
+
+// Notify implements Notifier.
+func (m *smtpMailer) Notify(ctx context.Context, msg Message) error {
+	return m.NotifyBatch(ctx, []Message{msg})
+}
+
+// NotifyBatch implements BatchNotifier: all messages go out as one email.
+func (m *smtpMailer) NotifyBatch(ctx context.Context, msgs []Message) error {
+	body, err := m.compose(msgs, time.Now())
+	if err != nil {
+		return err
+	}
+
+	c, err := m.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if m.tlsMode == TLSModeStartTLS {
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("smtp %s: server does not support STARTTLS", m.addr)
+		}
+		if err := c.StartTLS(m.tlsConfig); err != nil {
+			return fmt.Errorf("smtp %s: starttls: %w", m.addr, err)
+		}
+	}
+	if m.username != "" {
+		if err := c.Auth(smtp.PlainAuth("", m.username, m.password, m.host)); err != nil {
+			return fmt.Errorf("smtp %s: auth: %w", m.addr, err)
+		}
+	}
+
+	if err := c.Mail(m.envFrom); err != nil {
+		return fmt.Errorf("smtp %s: MAIL FROM: %w", m.addr, err)
+	}
+	for _, rcpt := range m.envTo {
+		if err := c.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("smtp %s: RCPT TO %s: %w", m.addr, rcpt, err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("smtp %s: DATA: %w", m.addr, err)
+	}
+	if _, err := w.Write(body); err != nil {
+		return fmt.Errorf("smtp %s: DATA: %w", m.addr, err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp %s: DATA: %w", m.addr, err)
+	}
+	return c.Quit()
+}
+
+// dial connects to the server, with TLS in implicit mode. The
+// connection is closed when ctx is done, since net/smtp has no
+// context support of its own.
+func (m *smtpMailer) dial(ctx context.Context) (*smtp.Client, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", m.addr)
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+
+	if m.tlsMode == TLSModeImplicit {
+		tc := tls.Client(conn, m.tlsConfig)
+		if err := tc.HandshakeContext(ctx); err != nil {
+			stop()
+			conn.Close()
+			return nil, fmt.Errorf("smtp %s: %w", m.addr, err)
+		}
+		conn = tc
+	}
+
+	c, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		stop()
+		conn.Close()
+		return nil, fmt.Errorf("smtp %s: %w", m.addr, err)
+	}
+	return c, nil
+}
+
+// compose renders the complete email: headers plus a multipart/
+// alternative body with plain-text and HTML parts.
+func (m *smtpMailer) compose(msgs []Message, now time.Time) ([]byte, error) {
+	var htmlBody bytes.Buffer
+	if err := emailTemplate.Execute(&htmlBody, emailView(msgs)); err != nil {
+		return nil, fmt.Errorf("render email: %w", err)
+	}
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+
+	header := []string{
+		"From: " + m.from,
+		"To: " + strings.Join(m.to, ", "),
+		"Subject: " + mime.QEncoding.Encode("utf-8", emailSubject(msgs)),
+		"Date: " + now.Format(time.RFC1123Z),
+		"Message-ID: " + messageID(m.host),
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/alternative; boundary=" + mw.Boundary(),
+	}
+	var out bytes.Buffer
+	out.WriteString(strings.Join(header, "\r\n") + "\r\n\r\n")
+
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", emailText(msgs)},
+		{"text/html; charset=utf-8", htmlBody.String()},
+	} {
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(pw)
+		if _, err := qp.Write([]byte(part.body)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	out.Write(buf.Bytes())
+	return out.Bytes(), nil
+}
+
+// emailSubject is the title of a single message, or a count of what
+// changed for a batch, e.g. "Aurora: 18 DOWN, 2 UP".
+func emailSubject(msgs []Message) string {
+	if len(msgs) == 1 {
+		return "Aurora: " + msgs[0].Title
+	}
+
+	counts := make(map[health.Status]int)
+	var order []health.Status
+	for _, msg := range msgs {
+		if counts[msg.New] == 0 {
+			order = append(order, msg.New)
+		}
+		counts[msg.New]++
+	}
+	parts := make([]string, len(order))
+	for i, st := range order {
+		parts[i] = strconv.Itoa(counts[st]) + " " + string(st)
+	}
+	return "Aurora: " + strings.Join(parts, ", ")
+}
+
+// emailText is the plain-text body: one paragraph per message.
+func emailText(msgs []Message) string {
+	var b strings.Builder
+	for i, msg := range msgs {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(msg.Text + "\n")
+		for _, f := range messageFields(msg) {
+			b.WriteString("  " + f.name + ": " + f.value + "\n")
+		}
+		if !msg.At.IsZero() {
+			b.WriteString("  At: " + msg.At.Format(time.RFC1123) + "\n")
+		}
+	}
+	return b.String()
+}
+
+// emailRow is one message in the HTML body.
+type emailRow struct {
+	Service string
+	Status  string
+	Color   string
+	Reason  string
+	Error   string
+	At      string
+}
+
+func emailView(msgs []Message) []emailRow {
+	rows := make([]emailRow, len(msgs))
+	for i, msg := range msgs {
+		color := "#2ecc71"
+		if msg.New == health.StatusDown {
+			color = "#e74c3c"
+		}
+		rows[i] = emailRow{
+			Service: msg.Service,
+			Status:  string(msg.New),
+			Color:   color,
+			Reason:  msg.ReasonLabel,
+			Error:   msg.Error,
+		}
+		if msg.Test {
+			rows[i].Error = msg.Text
+		}
+		if !msg.At.IsZero() {
+			rows[i].At = msg.At.Format("2006-01-02 15:04:05 MST")
+		}
+	}
+	return rows
+}
+
+var emailTemplate = template.Must(template.New("email").Parse(`<!DOCTYPE html>
+<html><body style="font-family: sans-serif">
+<table cellpadding="6" style="border-collapse: collapse">
+<tr style="text-align: left"><th>Service</th><th>Status</th><th>Reason</th><th>Last error</th><th>At</th></tr>
+{{range .}}<tr>
+<td>{{.Service}}</td>
+<td style="color: {{.Color}}; font-weight: bold">{{.Status}}</td>
+<td>{{.Reason}}</td>
+<td>{{.Error}}</td>
+<td>{{.At}}</td>
+</tr>
+{{end}}</table>
+</body></html>
+`))
+
+// messageID returns a unique Message-ID for the given domain.
+func messageID(domain string) string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return "<" + hex.EncodeToString(b) + "@" + domain + ">"
+}
@@ -0,0 +1,283 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/health"
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/models"
+)
+
+// email is what the stub SMTP server received in one session.
+type email struct {
+	tls  bool
+	auth string // decoded AUTH PLAIN response
+	from string
+	to   []string
+	data string
+}
+
+// smtpStub is a minimal SMTP server: it offers STARTTLS when it has a
+// certificate, accepts any AUTH PLAIN and records every mail.
+type smtpStub struct {
+	port     int
+	tls      *tls.Config // nil: no STARTTLS
+	implicit bool
+	mails    chan email
+}
+
+// newSMTPStub starts a stub server. With tlsMode starttls or implicit it
+// serves the httptest certificate, whose roots are returned.
+func newSMTPStub(t *testing.T, tlsMode string) (*smtpStub, *x509.CertPool) {
+	t.Helper()
+
+	s := &smtpStub{mails: make(chan email, 16)}
+	var roots *x509.CertPool
+	if tlsMode != TLSModeNone {
+		srv := httptest.NewTLSServer(http.NotFoundHandler())
+		t.Cleanup(srv.Close)
+		s.tls = &tls.Config{Certificates: srv.TLS.Certificates}
+		s.implicit = tlsMode == TLSModeImplicit
+		roots = srv.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	s.port = ln.Addr().(*net.TCPAddr).Port
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s, roots
+}
+
+func (s *smtpStub) serve(conn net.Conn) {
+	defer conn.Close()
+
+	var m email
+	if s.implicit {
+		conn = tls.Server(conn, s.tls)
+		m.tls = true
+	}
+	tp := textproto.NewConn(conn)
+	reply := func(lines ...string) { _ = tp.PrintfLine("%s", strings.Join(lines, "\r\n")) }
+
+	reply("220 stub ESMTP")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			if s.tls != nil && !m.tls {
+				reply("250-stub", "250-STARTTLS", "250 AUTH PLAIN")
+			} else {
+				reply("250-stub", "250 AUTH PLAIN")
+			}
+		case "STARTTLS":
+			reply("220 ready")
+			conn = tls.Server(conn, s.tls)
+			tp = textproto.NewConn(conn)
+			m.tls = true
+		case "AUTH":
+			_, resp, _ := strings.Cut(arg, " ")
+			b, _ := base64.StdEncoding.DecodeString(resp)
+			m.auth = string(b)
+			reply("235 ok")
+		case "MAIL":
+			m.from = strings.Trim(strings.TrimPrefix(arg, "FROM:"), "<>")
+			reply("250 ok")
+		case "RCPT":
+			m.to = append(m.to, strings.Trim(strings.TrimPrefix(arg, "TO:"), "<>"))
+			reply("250 ok")
+		case "DATA":
+			reply("354 go ahead")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			m.data = string(data)
+			s.mails <- m
+			reply("250 queued")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+// recvMail waits for the next mail the stub received.
+func (s *smtpStub) recvMail(t *testing.T) email {
+	t.Helper()
+	select {
+	case m := <-s.mails:
+		return m
+	case <-time.After(5 * time.Second):
+		t.Fatal("no mail received")
+		return email{}
+	}
+}
+
+// mailParts parses a received mail into its header and the decoded
+// bodies of its parts by content type.
+func mailParts(t *testing.T, data string) (mail.Header, map[string]string) {
+	t.Helper()
+
+	msg, err := mail.ReadMessage(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q, want multipart/alternative", msg.Header.Get("Content-Type"))
+	}
+	parts := make(map[string]string)
+	r := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		p, err := r.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(p) // multipart decodes quoted-printable
+		ct, _, _ := mime.ParseMediaType(p.Header.Get("Content-Type"))
+		parts[ct] = string(body)
+	}
+	return msg.Header, parts
+}
+
+func TestSMTPStartTLSWithAuth(t *testing.T) {
+	stub, roots := newSMTPStub(t, TLSModeStartTLS)
+	m := newSMTP(Target{
+		Host:     "127.0.0.1",
+		Port:     stub.port,
+		Username: "aurora",
+		Password: "hunter2",
+		From:     "Aurora <aurora@example.com>",
+		To:       []string{"ops@example.com", "Me <me@example.com>"},
+	})
+	m.tlsConfig.RootCAs = roots
+
+	if err := m.Notify(context.Background(), downMessage); err != nil {
+		t.Fatal(err)
+	}
+	got := stub.recvMail(t)
+	if !got.tls || got.auth != "\x00aurora\x00hunter2" {
+		t.Errorf("tls = %v, auth = %q; want STARTTLS and PLAIN auth", got.tls, got.auth)
+	}
+	if got.from != "aurora@example.com" || strings.Join(got.to, ",") != "ops@example.com,me@example.com" {
+		t.Errorf("envelope = %s -> %v", got.from, got.to)
+	}
+
+	header, parts := mailParts(t, got.data)
+	if s := header.Get("Subject"); s != "Aurora: Plex is DOWN" {
+		t.Errorf("Subject = %q", s)
+	}
+	if to := header.Get("To"); to != "ops@example.com, Me <me@example.com>" {
+		t.Errorf("To = %q", to)
+	}
+	if text := parts["text/plain"]; !strings.Contains(text, downMessage.Text) || !strings.Contains(text, "Reason: Connect") {
+		t.Errorf("text part = %q", text)
+	}
+	if html := parts["text/html"]; !strings.Contains(html, "<td>Plex</td>") || !strings.Contains(html, "connection &#34;refused&#34;") {
+		t.Errorf("html part = %q", html)
+	}
+}
+
+func TestSMTPImplicitTLS(t *testing.T) {
+	stub, roots := newSMTPStub(t, TLSModeImplicit)
+	m := newSMTP(Target{
+		Host:    "127.0.0.1",
+		Port:    stub.port,
+		TLSMode: TLSModeImplicit,
+		From:    "aurora@example.com",
+		To:      []string{"ops@example.com"},
+	})
+	m.tlsConfig.RootCAs = roots
+
+	if err := m.Notify(context.Background(), downMessage); err != nil {
+		t.Fatal(err)
+	}
+	if got := stub.recvMail(t); !got.tls || got.auth != "" {
+		t.Errorf("tls = %v, auth = %q; want implicit TLS without auth", got.tls, got.auth)
+	}
+}
+
+func TestSMTPRequiresStartTLS(t *testing.T) {
+	stub, _ := newSMTPStub(t, TLSModeNone)
+	m := newSMTP(Target{Host: "127.0.0.1", Port: stub.port, From: "aurora@example.com", To: []string{"ops@example.com"}})
+
+	err := m.Notify(context.Background(), downMessage)
+	if err == nil || !strings.Contains(err.Error(), "does not support STARTTLS") {
+		t.Fatalf("Notify = %v, want STARTTLS error", err)
+	}
+}
+
+func TestDispatcherBatchesEmail(t *testing.T) {
+	stub, _ := newSMTPStub(t, TLSModeNone)
+	d, err := New(Config{Targets: []Target{{
+		Name:        "mail",
+		Type:        "smtp",
+		Host:        "127.0.0.1",
+		Port:        stub.port,
+		TLSMode:     TLSModeNone,
+		From:        "aurora@example.com",
+		To:          []string{"ops@example.com"},
+		BatchWindow: 100 * time.Millisecond,
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var events []health.Event
+	for i := range 3 {
+		svc := models.Service{Name: "svc" + strconv.Itoa(i)}
+		events = append(events, health.Event{Service: svc, Old: health.StatusUp, New: health.StatusDown})
+	}
+	events = append(events, health.Event{Service: models.Service{Name: "NAS"}, Old: health.StatusDown, New: health.StatusUp})
+	run(t, d, events...)
+
+	header, parts := mailParts(t, stub.recvMail(t).data)
+	if s := header.Get("Subject"); s != "Aurora: 3 DOWN, 1 UP" {
+		t.Errorf("Subject = %q, want one email for the batch", s)
+	}
+	for _, name := range []string{"svc0", "svc1", "svc2", "NAS"} {
+		if !strings.Contains(parts["text/html"], "<td>"+name+"</td>") {
+			t.Errorf("html part does not list %s", name)
+		}
+	}
+	select {
+	case m := <-stub.mails:
+		t.Errorf("second email sent: %q", m.data)
+	default:
+	}
+}
@@ -0,0 +1,53 @@
+package notify
+
+import (
+	"context"
+	"html"
+	"net/http"
+	"strings"
+)
+
+// defaultTelegramURL is the Telegram Bot API.
+const defaultTelegramURL = "https://api.telegram.org"
+
+// telegram sends messages through a bot with the Bot API's sendMessage.
+// See https://core.telegram.org/bots/api#sendmessage.
+type telegram struct {
+	url     string
+	token   string
+	chatID  string
+	headers map[string]string
+	client  *http.Client
+}
+
+func newTelegram(t Target, client *http.Client) *telegram {
+	return &telegram{
+		url:     strings.TrimRight(orDefault(t.URL, defaultTelegramURL), "/"),
+		token:   t.Token,
+		chatID:  t.ChatID,
+		headers: t.Headers,
+		client:  client,
+	}
+}
+
+type telegramMessage struct {
+	ChatID                string `json:"chat_id"`
+	Text                  string `json:"text"`
+	ParseMode             string `json:"parse_mode"`
+	DisableWebPagePreview bool   `json:"disable_web_page_preview"`
+}
+
+// Notify implements Notifier.
+func (t *telegram) Notify(ctx context.Context, msg Message) error {
+	text := "<b>" + html.EscapeString(msg.Title) + "</b>\n" + html.EscapeString(msg.Text)
+	for _, f := range messageFields(msg) {
+		text += "\n<i>" + html.EscapeString(f.name) + ":</i> " + html.EscapeString(f.value)
+	}
+
+	return postJSON(ctx, t.client, t.url+"/bot"+t.token+"/sendMessage", t.headers, telegramMessage{
+		ChatID:                t.chatID,
+		Text:                  text,
+		ParseMode:             "HTML",
+		DisableWebPagePreview: true,
+	})
+}
@@ -0,0 +1,251 @@
+package notify
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/health"
+)
+
+// Problem is one invalid setting found by Validate. Field locates it
+// within the notifications section, e.g. "targets[2].url", so that
+// config.Load can report its line.
+type Problem struct {
+	Field   string
+	Message string
+
+	// First is the Field of the earlier definition a duplicate clashes
+	// with, or "".
+	First string
+}
+
+// knownTypes are the supported target types.
+var knownTypes = map[string]bool{
+	"": true, "webhook": true, "ntfy": true, "gotify": true, "discord": true, "slack": true, "telegram": true, "smtp": true,
+}
+
+// tlsModes are the tls_mode values of smtp targets.
+var tlsModes = map[string]bool{
+	"": true, TLSModeStartTLS: true, TLSModeImplicit: true, TLSModeNone: true,
+}
+
+// webhookMethods are the HTTP methods a webhook target may use.
+var webhookMethods = map[string]bool{"": true, "POST": true, "PUT": true, "PATCH": true, "GET": true}
+
+// knownReasons are the reason classes a route can match on.
+var knownReasons = map[health.ReasonClass]bool{
+	health.ReasonTimeout: true, health.ReasonDNS: true, health.ReasonConn: true, health.ReasonPermission: true,
+	health.ReasonTLS: true, health.ReasonHTTP: true, health.ReasonUnknown: true, health.ReasonOther: true,
+}
+
+// Validate checks the targets and routes of c and returns every
+// problem found.
+func (c Config) Validate() []Problem {
+	var ps []Problem
+	first := make(map[string]string, len(c.Targets))
+	for i, t := range c.Targets {
+		field := fmt.Sprintf("targets[%d]", i)
+		label := t.Name
+		if strings.TrimSpace(t.Name) == "" {
+			label = "notifications." + field
+			ps = append(ps, Problem{Field: field, Message: label + ": name is required"})
+		} else if f, dup := first[t.Name]; dup {
+			ps = append(ps, Problem{
+				Field:   field + ".name",
+				Message: fmt.Sprintf("duplicate notification target %q", t.Name),
+				First:   f,
+			})
+		} else {
+			first[t.Name] = field + ".name"
+		}
+		ps = append(ps, t.validate(field, label)...)
+	}
+
+	for i, r := range c.Routes {
+		ps = append(ps, r.validate(fmt.Sprintf("routes[%d]", i), first)...)
+	}
+	return ps
+}
+
+// validate checks the settings of one target at field.
+func (t Target) validate(field, label string) []Problem {
+	var ps []Problem
+	add := func(key, format string, args ...any) {
+		ps = append(ps, Problem{
+			Field:   joinField(field, key),
+			Message: label + ": " + fmt.Sprintf(format, args...),
+		})
+	}
+
+	if !knownTypes[t.Type] {
+		add("type", "unknown notification type %q (want webhook, ntfy, gotify, discord, slack, telegram or smtp)", t.Type)
+	}
+
+	typ := t.Type
+	if typ == "" {
+		typ = "webhook"
+	}
+	switch typ {
+	case "webhook", "gotify", "discord", "slack":
+		if t.URL == "" {
+			add("", "url is required for %s targets", typ)
+		}
+	case "ntfy":
+		if t.Topic == "" {
+			add("", "topic is required for ntfy targets")
+		}
+	case "telegram":
+		if t.ChatID == "" {
+			add("", "chat_id is required for telegram targets")
+		}
+	case "smtp":
+		ps = append(ps, t.validateSMTP(field, label)...)
+	}
+	if typ != "smtp" && t.BatchWindow != 0 {
+		add("batch_window", "batch_window only applies to smtp targets")
+	}
+	if t.Token == "" && (typ == "gotify" || typ == "telegram") {
+		add("", "token is required for %s targets", typ)
+	}
+	if t.URL != "" {
+		if u, err := url.Parse(t.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			add("url", "url must be an absolute http:// or https:// URL")
+		}
+	}
+	switch {
+	case typ == "ntfy" && (t.Priority < 0 || t.Priority > 5):
+		add("priority", "priority must be between 1 and 5 for ntfy")
+	case typ == "gotify" && (t.Priority < 0 || t.Priority > 10):
+		add("priority", "priority must be between 0 and 10 for gotify")
+	}
+	if typ != "webhook" && (t.Method != "" || t.Body != "") {
+		add("", "method and body only apply to webhook targets")
+	}
+
+	if typ == "webhook" {
+		if !webhookMethods[strings.ToUpper(t.Method)] {
+			add("method", "unsupported method %q (want POST, PUT, PATCH or GET)", t.Method)
+		}
+		if t.Body != "" {
+			if _, err := ParseTemplate(t.Name, t.Body); err != nil {
+				add("body", "body: %v", err)
+			}
+		}
+	}
+
+	if t.Timeout < 0 {
+		add("timeout", "timeout must not be negative")
+	}
+	if t.Retries != nil && *t.Retries < 0 {
+		add("retries", "retries must not be negative")
+	}
+	if t.RetryDelay < 0 {
+		add("retry_delay", "retry_delay must not be negative")
+	}
+	return ps
+}
+
+// validateSMTP checks the settings of an smtp target.
+func (t Target) validateSMTP(field, label string) []Problem {
+	var ps []Problem
+	add := func(key, format string, args ...any) {
+		ps = append(ps, Problem{
+			Field:   joinField(field, key),
+			Message: label + ": " + fmt.Sprintf(format, args...),
+		})
+	}
+
+	if t.Host == "" {
+		add("", "host is required for smtp targets")
+	}
+	if t.Port < 0 || t.Port > 65535 {
+		add("port", "port %d out of range (1-65535)", t.Port)
+	}
+	if !tlsModes[t.TLSMode] {
+		add("tls_mode", "unknown tls_mode %q (want starttls, implicit or none)", t.TLSMode)
+	}
+	if t.From == "" {
+		add("", "from is required for smtp targets")
+	} else if _, err := mail.ParseAddress(t.From); err != nil {
+		add("from", "from %q: %v", t.From, err)
+	}
+	if len(t.To) == 0 {
+		add("", "to is required for smtp targets")
+	}
+	for j, addr := range t.To {
+		if _, err := mail.ParseAddress(addr); err != nil {
+			add(fmt.Sprintf("to[%d]", j), "to %q: %v", addr, err)
+		}
+	}
+	if t.BatchWindow < 0 {
+		add("batch_window", "batch_window must not be negative")
+	}
+	return ps
+}
+
+// validate checks one route at field against the targets by name.
+func (r Route) validate(field string, targets map[string]string) []Problem {
+	label := "notifications." + field
+	var ps []Problem
+	add := func(key, format string, args ...any) {
+		ps = append(ps, Problem{
+			Field:   joinField(field, key),
+			Message: label + ": " + fmt.Sprintf(format, args...),
+		})
+	}
+	checkTargets := func(names []string, parent, prefix string) {
+		if len(names) == 0 {
+			add(parent, "%stargets is required", prefix)
+		}
+		for j, name := range names {
+			if _, ok := targets[name]; !ok {
+				add(joinField(parent, fmt.Sprintf("targets[%d]", j)), "%sunknown notification target %q", prefix, name)
+			}
+		}
+	}
+
+	m := r.Match
+	if m.Service != "" {
+		if _, err := path.Match(m.Service, ""); err != nil {
+			add("match.service", "service %q: %v", m.Service, err)
+		}
+	}
+	if m.Type != "" && !slices.Contains(health.CheckTypes, strings.ToLower(m.Type)) {
+		add("match.type", "unknown type %q", m.Type)
+	}
+	if m.Reason != "" && !knownReasons[health.ReasonClass(strings.ToUpper(m.Reason))] {
+		add("match.reason", "unknown reason %q (want timeout, dns, conn, permission, tls, http, unknown or other)", m.Reason)
+	}
+	if m.Time != "" {
+		if _, err := ParseTimeRange(m.Time); err != nil {
+			add("match.time", "%v", err)
+		}
+	}
+
+	checkTargets(r.Targets, "", "")
+	if r.Escalate != nil {
+		if r.Escalate.After <= 0 {
+			add("escalate.after", "escalate.after must be positive")
+		}
+		checkTargets(r.Escalate.Targets, "escalate", "escalate: ")
+	}
+	if r.Repeat < 0 {
+		add("repeat", "repeat must not be negative")
+	}
+	return ps
+}
+
+// joinField appends key to the dotted path field; either may be empty.
+func joinField(field, key string) string {
+	switch {
+	case key == "":
+		return field
+	case field == "":
+		return key
+	}
+	return field + "." + key
+}
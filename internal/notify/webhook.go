@@ -0,0 +1,102 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/template"
+)
+
+// webhook sends a Message as an HTTP request with a templated body.
+type webhook struct {
+	url     string
+	method  string
+	headers map[string]string
+	body    *template.Template // nil: JSON document
+	client  *http.Client
+}
+
+func newWebhook(t Target, client *http.Client) (*webhook, error) {
+	w := &webhook{
+		url:     t.URL,
+		method:  strings.ToUpper(t.Method),
+		headers: t.Headers,
+		client:  client,
+	}
+	if w.method == "" {
+		w.method = http.MethodPost
+	}
+	if t.Body != "" {
+		tmpl, err := ParseTemplate(t.Name, t.Body)
+		if err != nil {
+			return nil, err
+		}
+		w.body = tmpl
+	}
+	return w, nil
+}
+
+// Notify implements Notifier.
+func (w *webhook) Notify(ctx context.Context, msg Message) error {
+	var body bytes.Buffer
+	if w.body != nil {
+		if err := w.body.Execute(&body, msg); err != nil {
+			return fmt.Errorf("render body: %w", err)
+		}
+	} else if err := json.NewEncoder(&body).Encode(toJSON(msg)); err != nil {
+		return fmt.Errorf("render body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, w.method, w.url, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "aurora-homelab")
+	for k, v := range w.headers {
+		req.Header.Set(k, v)
+	}
+	return send(w.client, req)
+}
+
+// postJSON posts payload as JSON to url with the given extra headers.
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("render body: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "aurora-homelab")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	return send(client, req)
+}
+
+// send performs req and treats any non-2xx response as an error that
+// includes the start of the response body.
+func send(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		return nil
+	}
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+	if s := strings.TrimSpace(string(snippet)); s != "" {
+		return fmt.Errorf("unexpected status %s: %s", resp.Status, s)
+	}
+	return fmt.Errorf("unexpected status %s", resp.Status)
+}
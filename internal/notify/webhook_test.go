@@ -0,0 +1,108 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/health"
+)
+
+// request is what a test server received.
+type request struct {
+	method string
+	path   string
+	header http.Header
+	body   string
+}
+
+// newReceiver records requests and answers with status.
+func newReceiver(t *testing.T, status int) (*httptest.Server, <-chan request) {
+	t.Helper()
+
+	reqs := make(chan request, 16)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		reqs <- request{method: r.Method, path: r.URL.Path, header: r.Header, body: string(body)}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, reqs
+}
+
+var downMessage = Message{
+	Service:     "Plex",
+	Old:         health.StatusUp,
+	New:         health.StatusDown,
+	Reason:      health.ReasonConn,
+	ReasonLabel: "Connect",
+	Error:       `connection "refused"`,
+	Title:       "Plex is DOWN",
+	Text:        `Plex is DOWN (Connect): connection "refused"`,
+}
+
+func TestWebhookTemplatedBody(t *testing.T) {
+	srv, reqs := newReceiver(t, http.StatusNoContent)
+
+	w, err := newWebhook(Target{
+		Name:    "chat",
+		URL:     srv.URL,
+		Method:  "put",
+		Headers: map[string]string{"Authorization": "Bearer t0ken"},
+		Body:    `{"text": {{json .Text}}, "down": {{eq .New "DOWN"}}}`,
+	}, http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Notify(context.Background(), downMessage); err != nil {
+		t.Fatal(err)
+	}
+
+	got := <-reqs
+	if got.method != http.MethodPut || got.header.Get("Authorization") != "Bearer t0ken" {
+		t.Fatalf("request = %s %v", got.method, got.header)
+	}
+	var body struct {
+		Text string `json:"text"`
+		Down bool   `json:"down"`
+	}
+	if err := json.Unmarshal([]byte(got.body), &body); err != nil {
+		t.Fatalf("body %q: %v", got.body, err)
+	}
+	if body.Text != downMessage.Text || !body.Down {
+		t.Fatalf("body = %+v", body)
+	}
+}
+
+func TestWebhookDefaultBodyAndErrors(t *testing.T) {
+	srv, reqs := newReceiver(t, http.StatusOK)
+
+	w, err := newWebhook(Target{Name: "hook", URL: srv.URL}, http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Notify(context.Background(), downMessage); err != nil {
+		t.Fatal(err)
+	}
+	got := <-reqs
+	if got.method != http.MethodPost || got.header.Get("Content-Type") != "application/json" {
+		t.Fatalf("request = %s %v", got.method, got.header)
+	}
+	var body jsonMessage
+	if err := json.Unmarshal([]byte(got.body), &body); err != nil {
+		t.Fatalf("body %q: %v", got.body, err)
+	}
+	if body.Service != "Plex" || body.New != "DOWN" || body.Reason != "CONN" {
+		t.Fatalf("body = %+v", body)
+	}
+
+	failing, _ := newReceiver(t, http.StatusUnauthorized)
+	w.url = failing.URL
+	if err := w.Notify(context.Background(), downMessage); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("error = %v, want unexpected status 401", err)
+	}
+}
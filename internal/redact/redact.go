@@ -0,0 +1,112 @@
+// Package redact masks secret values (API tokens, passwords) in strings
+// before they reach results, logs or the dashboard.
+package redact
+
+import (
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Mask replaces every redacted secret.
+const Mask = "[REDACTED]"
+
+// minSecretLen skips values too short to redact without mangling
+// unrelated text.
+const minSecretLen = 4
+
+// Redactor replaces known secrets with Mask. The secret set can be
+// swapped at any time (e.g. on config reload). A nil *Redactor is
+// valid and redacts nothing.
+type Redactor struct {
+	mu       sync.RWMutex
+	replacer *strings.Replacer
+}
+
+// New returns a Redactor for the given secrets.
+func New(secrets ...string) *Redactor {
+	r := &Redactor{}
+	r.Set(secrets)
+	return r
+}
+
+// Set replaces the secrets being redacted.
+func (r *Redactor) Set(secrets []string) {
+	seen := make(map[string]bool)
+	var olds []string
+	for _, s := range secrets {
+		if len(s) < minSecretLen {
+			continue
+		}
+		// Secrets also show up escaped inside URLs in error messages.
+		for _, v := range []string{s, url.QueryEscape(s), url.PathEscape(s)} {
+			if !seen[v] {
+				seen[v] = true
+				olds = append(olds, v)
+			}
+		}
+	}
+
+	// Longest first so a secret containing another is masked whole.
+	sort.Slice(olds, func(i, j int) bool { return len(olds[i]) > len(olds[j]) })
+
+	var replacer *strings.Replacer
+	if len(olds) > 0 {
+		pairs := make([]string, 0, 2*len(olds))
+		for _, o := range olds {
+			pairs = append(pairs, o, Mask)
+		}
+		replacer = strings.NewReplacer(pairs...)
+	}
+
+	r.mu.Lock()
+	r.replacer = replacer
+	r.mu.Unlock()
+}
+
+// String returns s with every secret masked.
+func (r *Redactor) String(s string) string {
+	if r == nil || s == "" {
+		return s
+	}
+	r.mu.RLock()
+	replacer := r.replacer
+	r.mu.RUnlock()
+
+	if replacer == nil {
+		return s
+	}
+	return replacer.Replace(s)
+}
+
+// Writer returns a writer that masks secrets in everything written to
+// w. It is meant for line-oriented output such as a log.Logger, where
+// each Write holds a whole message.
+func (r *Redactor) Writer(w io.Writer) io.Writer {
+	return &writer{r: r, w: w}
+}
+
+type writer struct {
+	r *Redactor
+	w io.Writer
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(w.w, w.r.String(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// URL masks the password of a URL's userinfo ("user:pass@host"), the
+// only secret a URL can carry structurally. Unparseable input is
+// returned unchanged.
+func URL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.User == nil {
+		return raw
+	}
+	return u.Redacted()
+}
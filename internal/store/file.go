@@ -0,0 +1,308 @@
+// Package store provides persistent storage backends for health results.
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/health"
+)
+
+// Record kinds written to the log.
+const (
+	kindResult     = "result"
+	kindTransition = "transition"
+)
+
+// record is one line of the append-only log.
+type record struct {
+	Kind       string             `json:"kind"`
+	Result     *health.Result     `json:"result,omitempty"`
+	Transition *health.Transition `json:"transition,omitempty"`
+}
+
+// at returns the timestamp used for retention decisions.
+func (r record) at() time.Time {
+	if r.Result != nil {
+		return r.Result.CheckedAt
+	}
+	if r.Transition != nil {
+		return r.Transition.At
+	}
+	return time.Time{}
+}
+
+// service returns the service the record belongs to.
+func (r record) service() string {
+	if r.Result != nil {
+		return r.Result.ServiceName
+	}
+	if r.Transition != nil {
+		return r.Transition.ServiceName
+	}
+	return ""
+}
+
+// DefaultResultTail is how many results per service are kept when
+// WithResultTail is not used, matching the checker's default history.
+const DefaultResultTail = 120
+
+// FileStore is a health.Store backed by an append-only JSON-lines file.
+// Every result and transition is one line; Compact rewrites the file
+// without expired lines and keeps only the newest results of each
+// service, so the file stays small however long retention is.
+type FileStore struct {
+	mu   sync.Mutex
+	path string
+	f    *os.File
+	w    *bufio.Writer
+	tail int // results kept per service
+}
+
+var _ health.Store = (*FileStore)(nil)
+
+// Option customizes a FileStore opened by OpenFile.
+type Option func(*FileStore)
+
+// WithResultTail sets how many results per service are kept: the
+// history shown after a restart. Transitions are kept for the whole
+// retention. Values below 1 keep the default.
+func WithResultTail(n int) Option {
+	return func(s *FileStore) {
+		if n > 0 {
+			s.tail = n
+		}
+	}
+}
+
+// OpenFile opens (or creates) the log at path, creating parent
+// directories as needed.
+func OpenFile(path string, opts ...Option) (*FileStore, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("create store dir: %w", err)
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open store: %w", err)
+	}
+
+	s := &FileStore{path: path, f: f, w: bufio.NewWriter(f), tail: DefaultResultTail}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
+}
+
+// SaveResult appends a result record.
+func (s *FileStore) SaveResult(res health.Result) error {
+	return s.append(record{Kind: kindResult, Result: &res})
+}
+
+// SaveTransition appends a transition record.
+func (s *FileStore) SaveTransition(t health.Transition) error {
+	return s.append(record{Kind: kindTransition, Transition: &t})
+}
+
+func (s *FileStore) append(rec record) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("encode record: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.f == nil {
+		return errors.New("store is closed")
+	}
+	if _, err := s.w.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("write record: %w", err)
+	}
+	return s.w.Flush()
+}
+
+// Load replays the log, keeping the newest results of each service
+// (see WithResultTail). Lines that fail to decode (e.g. a partial
+// write after a crash) are skipped.
+func (s *FileStore) Load() (*health.StoredState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st := &health.StoredState{
+		Results:     make(map[string]health.Result),
+		History:     make(map[string][]health.HistoryEntry),
+		Transitions: make(map[string][]health.Transition),
+	}
+
+	err := s.scan(func(rec record) {
+		switch {
+		case rec.Result != nil:
+			res := *rec.Result
+			st.Results[res.ServiceName] = res
+			h := append(st.History[res.ServiceName], health.NewHistoryEntry(res))
+			if len(h) > s.tail {
+				h = h[len(h)-s.tail:]
+			}
+			st.History[res.ServiceName] = h
+		case rec.Transition != nil:
+			t := *rec.Transition
+			st.Transitions[t.ServiceName] = append(st.Transitions[t.ServiceName], t)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+// Compact rewrites the log without records older than before and
+// without results beyond the newest few of each service. The most
+// recent result of each service, and the last transition before
+// before, are always kept so the last known state (and the state a
+// retention window starts in) survive.
+func (s *FileStore) Compact(before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.f == nil {
+		return errors.New("store is closed")
+	}
+
+	recs, err := s.readAll()
+	if err != nil {
+		return err
+	}
+
+	keep := s.keep(recs, before)
+
+	tmp := s.path + ".tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("create compacted store: %w", err)
+	}
+
+	w := bufio.NewWriter(out)
+	enc := json.NewEncoder(w)
+	for i, rec := range recs {
+		if !keep[i] {
+			continue
+		}
+		if err := enc.Encode(rec); err != nil {
+			_ = out.Close()
+			_ = os.Remove(tmp)
+			return fmt.Errorf("write compacted store: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		_ = out.Close()
+		_ = os.Remove(tmp)
+		return fmt.Errorf("write compacted store: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("close compacted store: %w", err)
+	}
+
+	// Swap the compacted file in and reopen it for appending. The old
+	// file stays open until then, so a failed rename loses nothing.
+	if err := os.Rename(tmp, s.path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("replace store: %w", err)
+	}
+	_ = s.f.Close()
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		s.f = nil
+		return fmt.Errorf("reopen store: %w", err)
+	}
+	s.f = f
+	s.w = bufio.NewWriter(f)
+	return nil
+}
+
+// keep marks the records Compact writes back: per service, the newest
+// s.tail results that are not older than before (the newest result
+// regardless), transitions from before on and the last one before it.
+func (s *FileStore) keep(recs []record, before time.Time) []bool {
+	keep := make([]bool, len(recs))
+	results := make(map[string]int)
+	transitions := make(map[string]bool)
+	for i := len(recs) - 1; i >= 0; i-- {
+		rec := recs[i]
+		name, old := rec.service(), rec.at().Before(before)
+		switch rec.Kind {
+		case kindResult:
+			n := results[name]
+			keep[i] = n == 0 || (n < s.tail && !old)
+			results[name] = n + 1
+		case kindTransition:
+			keep[i] = !old || !transitions[name]
+			if old {
+				transitions[name] = true
+			}
+		}
+	}
+	return keep
+}
+
+// Close flushes and closes the log.
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.f == nil {
+		return nil
+	}
+	err := s.w.Flush()
+	if cerr := s.f.Close(); err == nil {
+		err = cerr
+	}
+	s.f = nil
+	return err
+}
+
+// readAll decodes every valid line in the log. Caller holds s.mu.
+func (s *FileStore) readAll() ([]record, error) {
+	var recs []record
+	err := s.scan(func(rec record) {
+		recs = append(recs, rec)
+	})
+	return recs, err
+}
+
+// scan calls fn with every valid line in the log, oldest first.
+// Caller holds s.mu.
+func (s *FileStore) scan(fn func(rec record)) error {
+	f, err := os.Open(s.path)
+	if err != nil {
+		return fmt.Errorf("open store: %w", err)
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for sc.Scan() {
+		var rec record
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			continue
+		}
+		if rec.Result == nil && rec.Transition == nil {
+			continue
+		}
+		fn(rec)
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("read store: %w", err)
+	}
+	return nil
+}
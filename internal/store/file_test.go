@@ -0,0 +1,158 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cyber-mountain-man/aurora-homelab-go/internal/health"
+)
+
+func openTemp(t *testing.T) (*FileStore, string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "data", "aurora.log")
+	s, err := OpenFile(path)
+	if err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	return s, path
+}
+
+func TestFileStoreRoundTrip(t *testing.T) {
+	s, path := openTemp(t)
+	now := time.Now().Truncate(time.Second)
+
+	mustSave(t, s.SaveResult(health.Result{ServiceName: "Plex", Status: health.StatusUp, RawStatus: health.StatusUp, CheckedAt: now.Add(-time.Minute), Latency: 7 * time.Millisecond}))
+	mustSave(t, s.SaveTransition(health.Transition{ServiceName: "Plex", From: health.StatusUp, To: health.StatusDown, At: now}))
+	mustSave(t, s.SaveResult(health.Result{ServiceName: "Plex", Status: health.StatusDown, RawStatus: health.StatusDown, CheckedAt: now, Error: "connection refused"}))
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// Simulate a crash mid-write.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	_, _ = f.WriteString(`{"kind":"result","result":{"Servi`)
+	_ = f.Close()
+
+	s2, err := OpenFile(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer s2.Close()
+
+	st, err := s2.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	last := st.Results["Plex"]
+	if last.Status != health.StatusDown || last.Error != "connection refused" || !last.CheckedAt.Equal(now) {
+		t.Fatalf("last result = %+v, want DOWN at %v", last, now)
+	}
+	if n := len(st.History["Plex"]); n != 2 {
+		t.Fatalf("history len=%d, want 2", n)
+	}
+	if st.History["Plex"][0].Latency != 7*time.Millisecond {
+		t.Fatalf("latency not preserved: %v", st.History["Plex"][0].Latency)
+	}
+	if ts := st.Transitions["Plex"]; len(ts) != 1 || ts[0].To != health.StatusDown {
+		t.Fatalf("transitions = %+v, want one UP->DOWN", ts)
+	}
+}
+
+func TestFileStoreCompactKeepsLatestPerService(t *testing.T) {
+	s, _ := openTemp(t)
+	now := time.Now()
+	old := now.Add(-48 * time.Hour)
+
+	mustSave(t, s.SaveResult(health.Result{ServiceName: "NAS", Status: health.StatusUp, CheckedAt: old}))
+	mustSave(t, s.SaveResult(health.Result{ServiceName: "NAS", Status: health.StatusUp, CheckedAt: old.Add(time.Minute)}))
+	mustSave(t, s.SaveTransition(health.Transition{ServiceName: "NAS", From: health.StatusUnknown, To: health.StatusUp, At: old}))
+	mustSave(t, s.SaveResult(health.Result{ServiceName: "Plex", Status: health.StatusUp, CheckedAt: old}))
+	mustSave(t, s.SaveResult(health.Result{ServiceName: "Plex", Status: health.StatusDown, CheckedAt: now}))
+
+	if err := s.Compact(now.Add(-24 * time.Hour)); err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+
+	// Appending still works after the file was swapped.
+	mustSave(t, s.SaveResult(health.Result{ServiceName: "Plex", Status: health.StatusUp, CheckedAt: now.Add(time.Second)}))
+
+	st, err := s.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if n := len(st.History["NAS"]); n != 1 {
+		t.Fatalf("NAS history len=%d, want only the latest old result", n)
+	}
+	if len(st.Transitions["NAS"]) != 1 {
+		t.Fatalf("expected NAS transition to survive compaction")
+	}
+	if n := len(st.History["Plex"]); n != 2 {
+		t.Fatalf("Plex history len=%d, want 2 recent results", n)
+	}
+	if st.Results["Plex"].Status != health.StatusUp {
+		t.Fatalf("Plex last status=%q, want UP", st.Results["Plex"].Status)
+	}
+}
+
+func TestFileStoreKeepsResultTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "aurora.log")
+	s, err := OpenFile(path, WithResultTail(3))
+	if err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+	defer s.Close()
+
+	now := time.Now()
+	for i := 10; i > 0; i-- {
+		mustSave(t, s.SaveResult(health.Result{ServiceName: "NAS", Status: health.StatusUp, CheckedAt: now.Add(-time.Duration(i) * time.Minute)}))
+	}
+	mustSave(t, s.SaveTransition(health.Transition{ServiceName: "NAS", From: health.StatusUnknown, To: health.StatusUp, At: now.Add(-72 * time.Hour)}))
+	mustSave(t, s.SaveTransition(health.Transition{ServiceName: "NAS", From: health.StatusUp, To: health.StatusDown, At: now.Add(-48 * time.Hour)}))
+	mustSave(t, s.SaveTransition(health.Transition{ServiceName: "NAS", From: health.StatusDown, To: health.StatusUp, At: now.Add(-time.Hour)}))
+
+	// Load trims the history even before compaction.
+	st, err := s.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if h := st.History["NAS"]; len(h) != 3 || !h[2].CheckedAt.Equal(now.Add(-time.Minute)) {
+		t.Fatalf("history = %+v, want the newest 3", h)
+	}
+
+	if err := s.Compact(now.Add(-24 * time.Hour)); err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 3 results, the transition the window starts in and the one inside it.
+	if n := strings.Count(string(data), "\n"); n != 5 {
+		t.Fatalf("compacted store has %d lines, want 5:\n%s", n, data)
+	}
+
+	st, err = s.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if ts := st.Transitions["NAS"]; len(ts) != 2 || ts[0].To != health.StatusDown {
+		t.Fatalf("transitions = %+v, want UP->DOWN (before the window) and DOWN->UP", ts)
+	}
+}
+
+func mustSave(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("save: %v", err)
+	}
+}